@@ -48,6 +48,13 @@ func color(s skat.Suit) string {
 	}
 }
 
+func greyedColor() string {
+	if !enableColor {
+		return ""
+	}
+	return "\x1b[90;47m"
+}
+
 func resetColor() string {
 	if !enableColor {
 		return ""
@@ -88,6 +95,34 @@ func renderCardRow(cards skat.CardSet, withNumbers bool) {
 	}
 }
 
+// Render the hand of the player on turn, greying out cards which may not be
+// played; those do not get a number
+func renderPlayableCardRow(cards skat.CardSet, legal skat.CardSet) {
+	for _, card := range cards {
+		if legal.Contains(card) {
+			fmt.Printf(" %s", ppCardLine1(card))
+		} else {
+			fmt.Printf(" %s%-2s%s", greyedColor(), card.Type.Pretty(), resetColor())
+		}
+	}
+	fmt.Printf("\n")
+	for _, card := range cards {
+		if legal.Contains(card) {
+			fmt.Printf(" %s", ppCardLine2(card))
+		} else {
+			fmt.Printf(" %s %s%s", greyedColor(), card.Suit.Pretty(), resetColor())
+		}
+	}
+	fmt.Printf("\n")
+	for i, card := range cards {
+		if legal.Contains(card) {
+			fmt.Printf(" %-2d", i)
+		} else {
+			fmt.Printf("   ")
+		}
+	}
+}
+
 func renderBlindedCardRow(ncards int) {
 	for i := 0; i < ncards; i = i + 1 {
 		fmt.Printf(" %s", ppBlindCardLine1())
@@ -217,29 +252,79 @@ const (
 )
 
 const (
-	DoDeclareSchneider      = "schneider"
-	DoDeclareSchwarz        = "schwarz"
-	DoDeclareOuvert         = "ouvert"
-	DoDeclareSelectDiamonds = "diamonds"
-	DoDeclareSelectHearts   = "hearts"
-	DoDeclareSelectSpades   = "spades"
-	DoDeclareSelectClubs    = "clubs"
-	DoDeclareSelectGrand    = "grand"
-	DoDeclareSelectNull     = "null"
-	DoDeclareCancel         = "cancel"
-	DoDeclareResetPushset   = "resetPushset"
-	DoDeclareDone           = "done"
+	DoKontra = "kontra"
+	DoRe     = "re"
+	DoResign = "resign"
+
+	DoPeekLastTrick = "peekLastTrick"
+	DoPeekSkat      = "peekSkat"
+
+	DoClaim       = "claim"
+	DoAcceptClaim = "acceptClaim"
+	DoRejectClaim = "rejectClaim"
+)
+
+const (
+	DoJunkTakeSkat   = "junkTakeSkat"
+	DoJunkPassSkatOn = "junkPassSkatOn"
+)
+
+const (
+	DoExchangeGive  = "exchangeGive"
+	DoExchangeTake  = "exchangeTake"
+	DoExchangeReset = "exchangeReset"
+	DoExchangeDone  = "exchangeDone"
+)
+
+const (
+	DoWatchDeclarer = "watchDeclarer"
+	DoWatchDefender = "watchDefender"
+	DoWatchNothing  = "watchNothing"
+)
+
+const (
+	DoDeclareSchneider        = "schneider"
+	DoDeclareSchwarz          = "schwarz"
+	DoDeclareOuvert           = "ouvert"
+	DoDeclareSelectDiamonds   = "diamonds"
+	DoDeclareSelectHearts     = "hearts"
+	DoDeclareSelectSpades     = "spades"
+	DoDeclareSelectClubs      = "clubs"
+	DoDeclareSelectGrand      = "grand"
+	DoDeclareSelectNull       = "null"
+	DoDeclareSelectRevolution = "revolution"
+	DoDeclareCancel           = "cancel"
+	DoDeclareResetPushset     = "resetPushset"
+	DoDeclareDone             = "done"
 )
 
 var (
 	ErrAbortedByUser = errors.New("action aborted by user")
 )
 
+var (
+	// set when the dealer chose not to watch any hand in the current game
+	dealerDeclinedWatch = false
+)
+
+func rules(st singleuser.ClientState) *skat.RuleSet {
+	if st.GameState.Rules == nil {
+		return skat.StandardRuleSet()
+	}
+	return st.GameState.Rules
+}
+
 func composeGameDeclaration(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleuser.ClientState, hand skat.CardSet) error {
 	var pushset skat.CardSet
 	var gtype skat.GameType
 	var modifiers skat.GameModifier
 	handCopy := sortedHand(gtype, hand)
+	baseModifiers := skat.NoGameModifiers
+	if len(hand) == 10 {
+		baseModifiers = skat.GameModifierHand
+	}
+	bid := st.GameState.LastBiddingCall
+	options := rules(st).EnumerateDeclarations(hand, bid, baseModifiers)
 	for {
 		modifiers = modifiers.Normalized()
 
@@ -253,40 +338,68 @@ func composeGameDeclaration(l *zap.SugaredLogger, gc *singleuser.GameClient, st
 		}
 
 		if gtype != 0 {
-			fmt.Printf("Game type: %s  Modifiers: %s\n", gtype.Pretty(), modifiers.Pretty())
+			fmt.Printf("Game type: %s  Modifiers: %s\n", gtype.Pretty(), rules(st).NormalizedModifiers(modifiers, gtype).Pretty())
+			valid := false
+			for _, option := range options {
+				if option.GameType != gtype || rules(st).NormalizedModifiers(option.Announced, gtype) != rules(st).NormalizedModifiers(modifiers, gtype) {
+					continue
+				}
+				valid = true
+				if option.Overbid {
+					fmt.Printf("Minimum value: %d, overbid unless you score more (bid: %d)\n", option.MinimumValue, bid)
+				} else {
+					fmt.Printf("Minimum value: %d (bid: %d)\n", option.MinimumValue, bid)
+				}
+			}
+			if !valid {
+				fmt.Printf("These announcements are not allowed\n")
+			}
 		} else {
 			fmt.Printf("No game type selected\n")
 		}
 
+		prompt := "Declare:\n" +
+			" toggle [s]chneider\n" +
+			" toggle [S]chwarz\n"
+		if rules(st).OuvertAllowed() {
+			prompt = prompt + " toggle [o]uvert\n"
+		}
+		prompt = prompt +
+			" [d]iamonds " + skat.SuitDiamonds.Pretty() + "\n" +
+			" [h]earts " + skat.SuitHearts.Pretty() + "\n" +
+			" [c]lubs " + skat.SuitClubs.Pretty() + "\n" +
+			" s[p]ades " + skat.SuitSpades.Pretty() + "\n" +
+			" [g]rand\n" +
+			" [n]ull\n"
+		actions := map[string]string{
+			"s": DoDeclareSchneider,
+			"S": DoDeclareSchwarz,
+			"d": DoDeclareSelectDiamonds,
+			"h": DoDeclareSelectHearts,
+			"c": DoDeclareSelectClubs,
+			"p": DoDeclareSelectSpades,
+			"g": DoDeclareSelectGrand,
+			"n": DoDeclareSelectNull,
+			"r": DoDeclareResetPushset,
+			"x": DoDeclareCancel,
+			"y": DoDeclareDone,
+		}
+		if rules(st).OuvertAllowed() {
+			actions["o"] = DoDeclareOuvert
+		}
+		if rules(st).Revolution && len(pushset) == 0 && len(hand) == 10 {
+			prompt = prompt + " [R]evolution\n"
+			actions["R"] = DoDeclareSelectRevolution
+		}
+		prompt = prompt +
+			" [r]eset pushed cards\n" +
+			" [0-9] push card\n" +
+			" [x] cancel\n" +
+			" [y] declare!\n"
+
 		action, cardIndex, err := intOrAction(
-			"Declare:\n"+
-				" toggle [s]chneider\n"+
-				" toggle [S]chwarz\n"+
-				" toggle [o]uvert\n"+
-				" [d]iamonds "+skat.SuitDiamonds.Pretty()+"\n"+
-				" [h]earts "+skat.SuitHearts.Pretty()+"\n"+
-				" [c]lubs "+skat.SuitClubs.Pretty()+"\n"+
-				" s[p]ades "+skat.SuitSpades.Pretty()+"\n"+
-				" [g]rand\n"+
-				" [n]ull\n"+
-				" [r]eset pushed cards\n"+
-				" [0-9] push card\n"+
-				" [x] cancel\n"+
-				" [y] declare!\n",
-			map[string]string{
-				"s": DoDeclareSchneider,
-				"S": DoDeclareSchwarz,
-				"o": DoDeclareOuvert,
-				"d": DoDeclareSelectDiamonds,
-				"h": DoDeclareSelectHearts,
-				"c": DoDeclareSelectClubs,
-				"p": DoDeclareSelectSpades,
-				"g": DoDeclareSelectGrand,
-				"n": DoDeclareSelectNull,
-				"r": DoDeclareResetPushset,
-				"x": DoDeclareCancel,
-				"y": DoDeclareDone,
-			},
+			prompt,
+			actions,
 			func(v int) error {
 				if len(handCopy) == 10 {
 					return fmt.Errorf("cannot push more cards")
@@ -345,6 +458,11 @@ func composeGameDeclaration(l *zap.SugaredLogger, gc *singleuser.GameClient, st
 				gtype = skat.GameTypeNull
 				sortHand(gtype, handCopy)
 			}
+		case DoDeclareSelectRevolution:
+			{
+				gtype = skat.GameTypeRevolution
+				sortHand(gtype, handCopy)
+			}
 		case DoDeclareSchneider:
 			{
 				if modifiers.Test(skat.GameModifierSchneiderAnnounced) {
@@ -399,9 +517,10 @@ func declarationPhaseDeclarer(l *zap.SugaredLogger, gc *singleuser.GameClient, s
 	startViewEx("Declare a game!", sortedHand(skat.InvalidGameType, gs.Hand), true)
 
 	for {
-		action, err := actionChoice("[t]ake Skat or [d]eclare a game", map[string]string{
+		action, err := actionChoice("[t]ake Skat, [d]eclare a game or [g]ive up", map[string]string{
 			"t": DoTakeSkat,
 			"d": DoDeclare,
+			"g": DoResign,
 		})
 		if err != nil {
 			l.Fatalw("failed to read input", "err", err)
@@ -419,6 +538,17 @@ func declarationPhaseDeclarer(l *zap.SugaredLogger, gc *singleuser.GameClient, s
 					return
 				}
 			}
+		case DoResign:
+			{
+				err = SimpleTimeout(func(ctx context.Context) error {
+					return gc.Resign(ctx)
+				})
+				if err != nil {
+					fmt.Printf("failed to resign: %s\n", err)
+				} else {
+					return
+				}
+			}
 		case DoDeclare:
 			{
 				err := composeGameDeclaration(l, gc, st, gs.Hand)
@@ -446,10 +576,28 @@ func playingPhase(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleuser
 		startView("Waiting for other player", nil)
 	}
 
+	if gs.GameType == skat.GameTypeJunk {
+		fmt.Printf("Playing Junk: everyone for themselves, the skat goes to the last trick\n\n")
+		renderSkatPasses(gs)
+	}
+	if gs.Multiplier > 1 {
+		fmt.Printf("Bock game: the score counts %d times\n\n", gs.Multiplier)
+	}
+	if st.Bock != nil && len(st.Bock.Pending) > 0 {
+		fmt.Printf("%d more special games pending\n\n", len(st.Bock.Pending))
+	}
+
 	for i, playerInfo := range gs.Players {
 		if i == st.PlayerIndex {
 			fmt.Printf("Your hand:\n")
-			renderCardRow(hand, myTurn)
+			if myTurn && gs.LegalCards != nil {
+				renderPlayableCardRow(hand, gs.LegalCards)
+			} else {
+				renderCardRow(hand, myTurn)
+			}
+		} else if len(gs.OpenHands) > i && gs.OpenHands[i] != nil {
+			fmt.Printf("Player %d (open):\n", i)
+			renderCardRow(sortedHand(gs.GameType, gs.OpenHands[i]), false)
 		} else {
 			fmt.Printf("Player %d:\n", i)
 			renderBlindedCardRow(playerInfo.Ncards)
@@ -461,18 +609,87 @@ func playingPhase(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleuser
 	renderCardRow(gs.Table, false)
 	fmt.Printf("\n")
 
+	if gs.LastTrick != nil {
+		fmt.Printf("Last trick, taken by player %d:\n", gs.LastTrickWinner)
+		renderCardRow(gs.LastTrick, false)
+		fmt.Printf("\n")
+	}
+	if gs.PushedCards != nil {
+		fmt.Printf("You pushed:\n")
+		renderCardRow(gs.PushedCards, false)
+		fmt.Printf("\n")
+	}
+
+	if gs.AnnouncedModifiers.Test(skat.GameModifierKontra) {
+		fmt.Printf("Kontra given by player %d\n", gs.KontraPlayer)
+	}
+	if gs.AnnouncedModifiers.Test(skat.GameModifierRe) {
+		fmt.Printf("Re given by the declarer\n")
+	}
+	for i, playerInfo := range gs.Players {
+		if playerInfo.Resigned {
+			fmt.Printf("Player %d gives the remaining tricks to the declarer\n", i)
+		}
+	}
+
+	if gs.Claim != nil {
+		claimPending(l, gc, st)
+		return
+	}
+
 	if !myTurn {
 		return
 	}
 
+	prompt := "pick a card"
+	actions := map[string]string{}
+	isDeclarer := gs.Declarer == st.PlayerIndex
+	if gs.GameType != skat.GameTypeJunk {
+		if rules(st).KontraRe && !isDeclarer && !gs.AnnouncedModifiers.Test(skat.GameModifierKontra) && len(gs.Hand) == 10 {
+			prompt = prompt + " or give [k]ontra"
+			actions["k"] = DoKontra
+		}
+		if isDeclarer && gs.AnnouncedModifiers.Test(skat.GameModifierKontra) && !gs.AnnouncedModifiers.Test(skat.GameModifierRe) {
+			prompt = prompt + " or give [r]e"
+			actions["r"] = DoRe
+		}
+		if isDeclarer && gs.PushedCards == nil && !gs.AnnouncedModifiers.Test(skat.GameModifierHand) {
+			prompt = prompt + " or see the [s]kat"
+			actions["s"] = DoPeekSkat
+		}
+		if !gs.Players[st.PlayerIndex].Resigned {
+			if isDeclarer {
+				prompt = prompt + " or [g]ive up"
+			} else {
+				prompt = prompt + " or [g]ive the remaining tricks to the declarer"
+			}
+			actions["g"] = DoResign
+		}
+	}
+	if gs.LastTrick == nil && len(gs.Hand) < 10 {
+		prompt = prompt + " or see the [l]ast trick"
+		actions["l"] = DoPeekLastTrick
+	}
+	canClaim := gs.GameType != skat.GameTypeJunk && len(gs.Table) == 0
+	if gs.GameType.IsNull() && !isDeclarer {
+		canClaim = false
+	}
+	if canClaim {
+		prompt = prompt + " or [c]laim the remaining tricks"
+		actions["c"] = DoClaim
+	}
+
 	for {
-		_, cardIndex, err := intOrAction(
-			"pick a card",
-			map[string]string{},
+		action, cardIndex, err := intOrAction(
+			prompt,
+			actions,
 			func(v int) error {
 				if v < 0 || v >= len(hand) {
 					return fmt.Errorf("card number out of bounds")
 				}
+				if gs.LegalCards != nil && !gs.LegalCards.Contains(hand[v]) {
+					return fmt.Errorf("you must follow suit")
+				}
 				return nil
 			},
 		)
@@ -481,6 +698,69 @@ func playingPhase(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleuser
 			l.Fatalw("input error", "err", err)
 		}
 
+		if action == DoResign {
+			err = SimpleTimeout(func(ctx context.Context) error {
+				return gc.Resign(ctx)
+			})
+			if err != nil {
+				fmt.Printf("failed to resign: %s\n", err)
+				continue
+			}
+			return
+		}
+
+		if action == DoClaim {
+			// in a Null game, the declarer claims to take no more tricks
+			tricks := len(gs.Hand)
+			if gs.GameType.IsNull() {
+				tricks = 0
+			}
+			err = SimpleTimeout(func(ctx context.Context) error {
+				return gc.Claim(ctx, tricks)
+			})
+			if err != nil {
+				fmt.Printf("failed to claim: %s\n", err)
+				continue
+			}
+			return
+		}
+
+		switch action {
+		case DoPeekLastTrick:
+			err = SimpleTimeout(func(ctx context.Context) error {
+				return gc.Peek(ctx, skat.PeekLastTrick)
+			})
+		case DoPeekSkat:
+			err = SimpleTimeout(func(ctx context.Context) error {
+				return gc.Peek(ctx, skat.PeekSkat)
+			})
+		}
+		if action == DoPeekLastTrick || action == DoPeekSkat {
+			if err != nil {
+				fmt.Printf("failed to peek: %s\n", err)
+				continue
+			}
+			return
+		}
+
+		switch action {
+		case DoKontra:
+			err = SimpleTimeout(func(ctx context.Context) error {
+				return gc.Kontra(ctx)
+			})
+		case DoRe:
+			err = SimpleTimeout(func(ctx context.Context) error {
+				return gc.Re(ctx)
+			})
+		}
+		if action != "" {
+			if err != nil {
+				fmt.Printf("failed to double: %s\n", err)
+				continue
+			}
+			return
+		}
+
 		card := hand[cardIndex]
 		err = SimpleTimeout(func(ctx context.Context) error {
 			return gc.PlayCard(ctx, card)
@@ -493,37 +773,598 @@ func playingPhase(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleuser
 	}
 }
 
+func claimPending(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleuser.ClientState) {
+	gs := st.GameState
+	claim := gs.Claim
+	if gs.GameType.IsNull() {
+		fmt.Printf("Player %d claims to take no more tricks\n", claim.Player)
+	} else {
+		fmt.Printf("Player %d claims %d of the remaining tricks\n", claim.Player, claim.Tricks)
+	}
+
+	claimingDeclarer := claim.Player == gs.Declarer
+	if (st.PlayerIndex == gs.Declarer) == claimingDeclarer || claim.Accepted[st.PlayerIndex] {
+		fmt.Printf("Waiting for the other players to respond\n")
+		return
+	}
+
+	for {
+		action, err := actionChoice(
+			"[a]ccept or [r]eject the claim",
+			map[string]string{
+				"a": DoAcceptClaim,
+				"r": DoRejectClaim,
+			},
+		)
+		if err != nil {
+			l.Fatalw("input error", "err", err)
+		}
+
+		err = SimpleTimeout(func(ctx context.Context) error {
+			return gc.ReplyToClaim(ctx, action == DoAcceptClaim)
+		})
+		if err != nil {
+			fmt.Printf("failed to respond to claim: %s\n", err)
+			continue
+		}
+		return
+	}
+}
+
+func renderSkatPasses(gs *skat.BlindedGameState) {
+	if len(gs.SkatPasses) == 0 {
+		return
+	}
+	for _, pass := range gs.SkatPasses {
+		if pass.Pushed {
+			fmt.Printf("Player %d pushed the skat on\n", pass.Player)
+		} else {
+			fmt.Printf("Player %d passed the skat on\n", pass.Player)
+		}
+	}
+	fmt.Printf("\n")
+}
+
+func junkPushingPhase(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleuser.ClientState) {
+	gs := st.GameState
+	myTurn := gs.CurrentPlayer == st.PlayerIndex
+	skatTaken := myTurn && gs.SkatCards == 0
+	hand := sortedHand(skat.GameTypeJunk, gs.Hand)
+
+	startView("Schieberamsch: each push doubles the result", nil)
+	renderSkatPasses(gs)
+	fmt.Printf("Your hand:\n")
+	renderCardRow(hand, skatTaken)
+	fmt.Printf("\n")
+
+	if !myTurn {
+		fmt.Printf("Waiting for player %d to decide on the skat\n", gs.CurrentPlayer)
+		endView()
+		return
+	}
+
+	if !skatTaken {
+		action, err := actionChoice(
+			"[t]ake the skat or [p]ass it on",
+			map[string]string{
+				"t": DoJunkTakeSkat,
+				"p": DoJunkPassSkatOn,
+			},
+		)
+		if err != nil {
+			l.Fatalw("input error", "err", err)
+		}
+
+		err = SimpleTimeout(func(ctx context.Context) error {
+			if action == DoJunkTakeSkat {
+				return gc.TakeSkat(ctx)
+			}
+			return gc.PassSkatOn(ctx)
+		})
+		if err != nil {
+			l.Fatalw("failed to decide on the skat",
+				"err", err,
+			)
+		}
+		return
+	}
+
+	for {
+		pushset := skat.CardSet{}
+		for len(pushset) < 2 {
+			_, cardIndex, err := intOrAction(
+				fmt.Sprintf("pick card %d to push on (no jacks)", len(pushset)+1),
+				map[string]string{},
+				func(v int) error {
+					if v < 0 || v >= len(hand) {
+						return fmt.Errorf("card number out of bounds")
+					}
+					if hand[v].Type == skat.CardJack {
+						return fmt.Errorf("jacks must not be pushed on")
+					}
+					if pushset.Contains(hand[v]) {
+						return fmt.Errorf("card already picked")
+					}
+					return nil
+				},
+			)
+			if err != nil {
+				l.Fatalw("input error", "err", err)
+			}
+			pushset = append(pushset, hand[cardIndex])
+		}
+
+		err := SimpleTimeout(func(ctx context.Context) error {
+			return gc.PushSkatOn(ctx, pushset)
+		})
+		if err != nil {
+			fmt.Printf("failed to push the skat on: %s\n", err)
+			continue
+		}
+		return
+	}
+}
+
+func exchangePhase(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleuser.ClientState) {
+	gs := st.GameState
+	hand := sortedHand(gs.GameType, gs.Hand)
+	partnerHand := sortedHand(gs.GameType, gs.PartnerHand)
+
+	startView("Revolution: the defenders exchange cards", nil)
+	fmt.Printf("Player %d plays Revolution with the open hand:\n", gs.Declarer)
+	renderCardRow(sortedHand(gs.GameType, gs.OpenHands[gs.Declarer]), false)
+	fmt.Printf("\n")
+
+	if st.PlayerIndex == gs.Declarer || gs.Players[st.PlayerIndex].Exchanged {
+		fmt.Printf("Your hand:\n")
+		renderCardRow(hand, false)
+		fmt.Printf("\n")
+		fmt.Printf("Waiting for the defenders to exchange cards\n")
+		endView()
+		return
+	}
+
+	var give, take skat.CardSet
+	for {
+		fmt.Printf("Your hand:\n")
+		renderCardRow(hand, true)
+		fmt.Printf("\nHand of your partner:\n")
+		renderCardRow(partnerHand, true)
+		fmt.Printf("\n")
+		if len(give) > 0 || len(take) > 0 {
+			fmt.Printf("Giving:\n")
+			renderCardRow(give, false)
+			fmt.Printf("Taking:\n")
+			renderCardRow(take, false)
+			fmt.Printf("\n")
+		}
+
+		action, err := actionChoice(
+			"[g]ive a card, [t]ake a card, [r]eset or [y] done exchanging",
+			map[string]string{
+				"g": DoExchangeGive,
+				"t": DoExchangeTake,
+				"r": DoExchangeReset,
+				"y": DoExchangeDone,
+			},
+		)
+		if err != nil {
+			l.Fatalw("input error", "err", err)
+		}
+
+		switch action {
+		case DoExchangeGive, DoExchangeTake:
+			{
+				source := hand
+				picked := &give
+				if action == DoExchangeTake {
+					source = partnerHand
+					picked = &take
+				}
+				_, cardIndex, err := intOrAction(
+					"pick a card",
+					map[string]string{},
+					func(v int) error {
+						if v < 0 || v >= len(source) {
+							return fmt.Errorf("card number out of bounds")
+						}
+						if picked.Contains(source[v]) {
+							return fmt.Errorf("card already picked")
+						}
+						return nil
+					},
+				)
+				if err != nil {
+					l.Fatalw("input error", "err", err)
+				}
+				*picked = append(*picked, source[cardIndex])
+			}
+		case DoExchangeReset:
+			{
+				give = nil
+				take = nil
+			}
+		case DoExchangeDone:
+			{
+				if len(give) != len(take) {
+					fmt.Printf("you need to give as many cards as you take\n")
+					continue
+				}
+				err = SimpleTimeout(func(ctx context.Context) error {
+					return gc.ExchangeCards(ctx, give, take)
+				})
+				if err != nil {
+					fmt.Printf("failed to exchange cards: %s\n", err)
+					continue
+				}
+				return
+			}
+		}
+	}
+}
+
+func renderReveal(st singleuser.ClientState) {
+	gs := st.GameState
+	if gs.Reveal == nil {
+		return
+	}
+	for i, hand := range gs.Reveal.Hands {
+		name := fmt.Sprintf("Player %d", i)
+		if i == st.PlayerIndex {
+			name = "You"
+		}
+		fmt.Printf("%s started with:\n", name)
+		renderCardRow(sortedHand(gs.GameType, hand), false)
+		fmt.Printf("\n")
+	}
+	fmt.Printf("Skat:\n")
+	renderCardRow(gs.Reveal.Skat, false)
+	fmt.Printf("\n")
+	if len(gs.Reveal.Pushed) > 0 {
+		fmt.Printf("Pushed:\n")
+		renderCardRow(gs.Reveal.Pushed, false)
+		fmt.Printf("\n")
+	}
+	fmt.Printf("\n")
+}
+
+func renderTrickHistory(st singleuser.ClientState) {
+	gs := st.GameState
+	if len(gs.Tricks) == 0 {
+		return
+	}
+	fmt.Printf("Tricks:\n")
+	for i, trick := range gs.Tricks {
+		fmt.Printf("%2d:", i+1)
+		for j, card := range trick.Cards {
+			marker := " "
+			if trick.Players[j] == trick.Winner {
+				marker = "*"
+			}
+			fmt.Printf(" %s%s(%d)", marker, card.Pretty(), trick.Players[j])
+		}
+		fmt.Printf("\n")
+	}
+	fmt.Printf("\n")
+}
+
+// Recompute the deal of a finished game and raise an alarm if it was not fair
+func renderDealVerification(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleuser.ClientState) {
+	err := gc.VerifyDeal(st)
+	switch err {
+	case nil:
+		if st.GameState.SealedDeal != nil {
+			fmt.Printf("Deal verified: the cards match the revealed keys.\n")
+		} else {
+			fmt.Printf("Deal verified: the cards match the revealed seeds.\n")
+		}
+	case singleuser.ErrDealNotObserved:
+		fmt.Printf("Deal not verified: this client did not see the hand as dealt.\n")
+	case skat.ErrMissingKey:
+		fmt.Printf("Deal not verified: a player did not reveal their key in time.\n")
+	default:
+		l.Errorw("deal verification failed",
+			"err", err,
+		)
+		fmt.Printf(
+			"\n%s!!! FAIRNESS ALARM: %s !!!%s\n",
+			color(skat.SuitHearts),
+			err,
+			resetColor(),
+		)
+		fmt.Printf("The cards of this game were not dealt as the seeds demand.\n\n")
+	}
+}
+
+func scoredJunkView(st singleuser.ClientState) {
+	gs := st.GameState
+	startView("Junk game is over", nil)
+
+	for i, playerInfo := range gs.Players {
+		name := fmt.Sprintf("Player %d", i)
+		if i == st.PlayerIndex {
+			name = "You"
+		}
+		fmt.Printf(
+			"%s: %d points, score %d\n",
+			name,
+			playerInfo.WonCardPoints,
+			playerInfo.AwardedScore,
+		)
+	}
+	fmt.Printf("\n")
+
+	if gs.FinalModifiers != skat.NoGameModifiers {
+		fmt.Printf("Modifiers: %s\n", gs.FinalModifiers.Pretty())
+	}
+	renderSkatPasses(gs)
+	fmt.Printf(
+		"Game value: %d\n",
+		gs.FinalGameValue,
+	)
+	fmt.Printf("\n")
+
+	renderReveal(st)
+	renderTrickHistory(st)
+
+	if st.PlayerIndex != skat.PlayerNone {
+		if gs.Players[st.PlayerIndex].AwardedScore >= 0 {
+			fmt.Printf("Congratulations!\n")
+		} else {
+			fmt.Printf("Better luck next time!\n")
+		}
+	}
+
+	endView()
+}
+
+func renderScoreSheet(st singleuser.ClientState) {
+	sheet := st.ScoreSheet
+	if sheet == nil || len(sheet.Entries) == 0 {
+		return
+	}
+
+	fmt.Printf("Score sheet:\n")
+	fmt.Printf("  # ")
+	for seat := range sheet.Totals {
+		name := fmt.Sprintf("Seat %d", seat)
+		if seat == st.Seat {
+			name = "You"
+		}
+		fmt.Printf(" %7s", name)
+	}
+	fmt.Printf("  Game\n")
+
+	for i, entry := range sheet.Entries {
+		fmt.Printf("%3d ", i+1)
+		for _, score := range entry.Scores {
+			fmt.Printf(" %7d", score)
+		}
+		if entry.PassedIn {
+			fmt.Printf("  passed in\n")
+		} else if entry.Declarer == skat.PlayerNone {
+			fmt.Printf("  %s %d\n", entry.GameType.Pretty(), entry.GameValue)
+		} else {
+			outcome := "lost"
+			if entry.Won {
+				outcome = "won"
+			}
+			fmt.Printf("  %s %d %s by seat %d\n", entry.GameType.Pretty(), entry.GameValue, outcome, entry.Declarer)
+		}
+	}
+
+	fmt.Printf("sum ")
+	for _, total := range sheet.Totals {
+		fmt.Printf(" %7d", total)
+	}
+	fmt.Printf("\n\n")
+}
+
+func dealerView(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleuser.ClientState) {
+	gs := st.GameState
+	startView("You are dealing and sit out this game", nil)
+
+	gameType := gs.GameType
+	if gs.Phase != skat.PhasePlaying {
+		gameType = skat.GameTypeGrand
+	}
+	for i, playerInfo := range gs.Players {
+		if i == gs.WatchedPlayer {
+			fmt.Printf("Player %d (watching):\n", i)
+			renderCardRow(sortedHand(gameType, gs.Hand), false)
+		} else {
+			fmt.Printf("Player %d:\n", i)
+			renderBlindedCardRow(playerInfo.Ncards)
+		}
+		fmt.Printf("\n")
+	}
+
+	if gs.Phase == skat.PhasePlaying {
+		fmt.Printf("Table:\n")
+		renderCardRow(gs.Table, false)
+		fmt.Printf("\n")
+	}
+
+	canWatch := gs.Phase == skat.PhaseDeclaration || gs.Phase == skat.PhasePlaying
+	if gs.Phase == skat.PhasePlaying && gs.GameType == skat.GameTypeJunk {
+		canWatch = false
+	}
+	if gs.SealedDeal != nil {
+		// nobody but the players can see the sealed hands
+		canWatch = false
+	}
+	if !canWatch || gs.WatchedPlayer != skat.PlayerNone || dealerDeclinedWatch {
+		endView()
+		return
+	}
+
+	for {
+		action, err := actionChoice(
+			"watch the [d]eclarer, a de[f]ender or [n]othing",
+			map[string]string{
+				"d": DoWatchDeclarer,
+				"f": DoWatchDefender,
+				"n": DoWatchNothing,
+			},
+		)
+		if err != nil {
+			l.Fatalw("input error", "err", err)
+		}
+
+		player := gs.Declarer
+		switch action {
+		case DoWatchNothing:
+			dealerDeclinedWatch = true
+			return
+		case DoWatchDefender:
+			player = (gs.Declarer + 1) % 3
+		}
+
+		err = SimpleTimeout(func(ctx context.Context) error {
+			return gc.WatchHand(ctx, player)
+		})
+		if err != nil {
+			fmt.Printf("failed to watch hand: %s\n", err)
+			continue
+		}
+		return
+	}
+}
+
+// Take the next step of a sealed deal; returns true if an action was sent
+func advanceSealedDeal(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleuser.ClientState) bool {
+	var sent bool
+	err := SimpleTimeout(func(ctx context.Context) error {
+		var err error
+		sent, err = gc.AdvanceSealedDeal(ctx, st)
+		return err
+	})
+	if err != nil {
+		l.Fatalw("failed to take part in the sealed deal",
+			"err", err,
+		)
+	}
+	return sent
+}
+
+func allSeedsProvided(gs *skat.BlindedGameState) bool {
+	for _, playerInfo := range gs.Players {
+		if !playerInfo.SeedProvided {
+			return false
+		}
+	}
+	return !gs.WithDealer || gs.DealerSeedProvided
+}
+
+func renderSealedDealStatus(gs *skat.BlindedGameState) {
+	d := gs.SealedDeal
+	for i, key := range d.PublicKeys {
+		status := "Not ready"
+		if d.SecretKeys[i] != nil {
+			status = "Key revealed"
+		} else if d.NextStripper() == i {
+			status = "Uncovering cards"
+		} else if d.NextShuffler() == i {
+			status = "Shuffling"
+		} else if key != nil {
+			status = "Key published"
+		}
+		fmt.Printf("  %d: %s\n", i+1, status)
+	}
+}
+
+func seedStatus(committed bool, provided bool) string {
+	if provided {
+		return "Ready"
+	}
+	if committed {
+		return "Committed"
+	}
+	return "Not ready"
+}
+
 func HandleGameState(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleuser.ClientState) {
 	gs := st.GameState
+	isDealer := st.PlayerIndex == skat.PlayerNone
+	if isDealer && gs.Phase != skat.PhaseInit && gs.Phase != skat.PhaseScored && gs.Phase != skat.PhasePassedIn {
+		dealerView(l, gc, st)
+		return
+	}
+
 	switch gs.Phase {
 	case skat.PhaseInit:
 		{
-			if !gs.Players[st.PlayerIndex].SeedProvided {
-				seed, err := skat.GenerateSeed()
+			dealerDeclinedWatch = false
+
+			seedCommitted := gs.DealerSeedCommitted
+			seedProvided := gs.DealerSeedProvided
+			if !isDealer {
+				seedCommitted = gs.Players[st.PlayerIndex].SeedCommitted
+				seedProvided = gs.Players[st.PlayerIndex].SeedProvided
+			}
+			if !seedCommitted {
+				if st.LastGame != nil {
+					HandleGameState(l, gc, singleuser.ClientState{
+						PlayerIndex: st.LastGame.YourPlayerIndex,
+						GameState:   st.LastGame.GameState,
+					})
+					renderScoreSheet(st)
+					_, err := actionChoice("[c]ontinue with the next game", map[string]string{"c": "continue"})
+					if err != nil {
+						l.Fatalw("input error", "err", err)
+					}
+				}
+
+				err := SimpleTimeout(func(ctx context.Context) error {
+					return gc.CommitSeed(ctx, gs)
+				})
 				if err != nil {
-					l.Fatalw("failed to generate game seed",
+					l.Fatalw("failed to commit to seed",
 						"err", err,
 					)
 				}
+				return
+			}
 
-				err = SimpleTimeout(func(ctx context.Context) error {
-					return gc.SetSeed(ctx, seed)
+			if gs.SeedsCommitted && !seedProvided {
+				err := SimpleTimeout(func(ctx context.Context) error {
+					return gc.RevealSeed(ctx, gs)
 				})
+				if err == singleuser.ErrServerSeedMismatch {
+					l.Fatalw("server seed does not match the commitment of the server, refusing to play",
+						"serverSeed", gs.ServerSeed,
+						"serverCommitment", gs.ServerCommitment,
+					)
+				}
+				if err == singleuser.ErrCommitmentChanged {
+					l.Fatalw("server changed its commitment after the seed was committed to, refusing to play",
+						"serverCommitment", gs.ServerCommitment,
+					)
+				}
 				if err != nil {
-					l.Fatalw("failed to set seed",
+					l.Fatalw("failed to reveal seed",
 						"err", err,
 					)
 				}
 				return
 			}
 
+			if gs.SealedDeal != nil && allSeedsProvided(gs) {
+				if advanceSealedDeal(l, gc, st) {
+					return
+				}
+				startView("Dealing sealed cards ...", nil)
+				renderSealedDealStatus(gs)
+				endView()
+				return
+			}
+
 			startView("Waiting for other players ...", nil)
 			for index, playerInfo := range gs.Players {
-				ready_s := "Not ready"
-				if playerInfo.SeedProvided {
-					ready_s = "Ready"
-				}
-				fmt.Printf("  %d: %s\n", index+1, ready_s)
+				fmt.Printf("  %d: %s\n", index+1, seedStatus(playerInfo.SeedCommitted, playerInfo.SeedProvided))
+			}
+			if gs.WithDealer {
+				fmt.Printf("  Dealer: %s\n", seedStatus(gs.DealerSeedCommitted, gs.DealerSeedProvided))
 			}
 			endView()
 		}
@@ -535,23 +1376,32 @@ func HandleGameState(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleu
 				fmt.Printf("Current highest: %d\n", bs.LastBid)
 			}
 			if bs.Caller == st.PlayerIndex && !bs.AwaitingResponse {
-				action, call, err := intOrAction("[p]ass or call", map[string]string{
+				prompt := "[p]ass or call"
+				actions := map[string]string{
 					"p": "pass",
-				}, func(v int) error {
-					if v < 18 {
-						return fmt.Errorf("must be at least 18")
-					}
-					if v < bs.LastBid {
+				}
+				if bs.NextBid != skat.BidNone {
+					prompt = fmt.Sprintf("[p]ass, [n]ext (%d) or call", bs.NextBid)
+					actions["n"] = "next"
+				}
+				action, call, err := intOrAction(prompt, actions, func(v int) error {
+					if v <= bs.LastBid {
 						return fmt.Errorf("must be higher than last bid")
 					}
+					if !rules(st).IsValidBid(v) {
+						return fmt.Errorf("not a valid bid, next would be %d", rules(st).NextBid(v))
+					}
 					return nil
 				})
 				if err != nil {
 					l.Fatalw("bogus input", "err", err)
 				}
 
-				if action == "pass" {
+				switch action {
+				case "pass":
 					call = skat.BidPass
+				case "next":
+					call = bs.NextBid
 				}
 
 				err = SimpleTimeout(func(ctx context.Context) error {
@@ -596,7 +1446,12 @@ func HandleGameState(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleu
 		}
 	case skat.PhaseDeclaration:
 		{
-			if gs.Declarer == st.PlayerIndex {
+			if gs.SealedDeal != nil && gs.SealedDeal.NextStripper() != skat.PlayerNone {
+				if advanceSealedDeal(l, gc, st) {
+					return
+				}
+				startView("Waiting for the skat to be uncovered...", gs.Hand)
+			} else if gs.Declarer == st.PlayerIndex {
 				declarationPhaseDeclarer(l, gc, st)
 			} else {
 				startView("Waiting for declarer...", gs.Hand)
@@ -607,8 +1462,48 @@ func HandleGameState(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleu
 		{
 			playingPhase(l, gc, st)
 		}
+	case skat.PhaseJunkPushing:
+		{
+			junkPushingPhase(l, gc, st)
+		}
+	case skat.PhaseExchange:
+		{
+			exchangePhase(l, gc, st)
+		}
+	case skat.PhasePassedIn:
+		{
+			if gs.SealedDeal == nil {
+				renderDealVerification(l, gc, st)
+			}
+			startView("Everyone passed, the cards will be dealt again", nil)
+			endView()
+		}
+	case skat.PhaseAudit:
+		{
+			if advanceSealedDeal(l, gc, st) {
+				return
+			}
+			startView("Revealing the keys to check the game ...", nil)
+			renderSealedDealStatus(gs)
+			endView()
+		}
 	case skat.PhaseScored:
 		{
+			renderDealVerification(l, gc, st)
+			if gs.SealedDeal != nil && gs.SealedDeal.Cheater != skat.PlayerNone {
+				fmt.Printf(
+					"\n%sPlayer %d was caught cheating and loses the game.%s\n\n",
+					color(skat.SuitHearts),
+					gs.SealedDeal.Cheater,
+					resetColor(),
+				)
+			}
+
+			if gs.GameType == skat.GameTypeJunk {
+				scoredJunkView(st)
+				return
+			}
+
 			condition := "lost"
 			if gs.LossReason == "" {
 				condition = "won"
@@ -662,6 +1557,14 @@ func HandleGameState(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleu
 					{
 						fmt.Printf("Not a null game\n")
 					}
+				case skat.LossReasonResigned:
+					{
+						fmt.Printf("The declarer gave up\n")
+					}
+				case skat.LossReasonCheated:
+					{
+						fmt.Printf("The declarer was caught cheating\n")
+					}
 				default:
 					{
 						fmt.Printf("?!\n")
@@ -669,10 +1572,15 @@ func HandleGameState(l *zap.SugaredLogger, gc *singleuser.GameClient, st singleu
 				}
 			}
 
-			if (gs.Declarer != st.PlayerIndex && gs.LossReason != "") || (gs.Declarer == st.PlayerIndex && gs.LossReason == "") {
-				fmt.Printf("Congratulations!\n")
-			} else {
-				fmt.Printf("Better luck next time!\n")
+			renderReveal(st)
+			renderTrickHistory(st)
+
+			if !isDealer {
+				if (gs.Declarer != st.PlayerIndex && gs.LossReason != "") || (gs.Declarer == st.PlayerIndex && gs.LossReason == "") {
+					fmt.Printf("Congratulations!\n")
+				} else {
+					fmt.Printf("Better luck next time!\n")
+				}
 			}
 
 			endView()
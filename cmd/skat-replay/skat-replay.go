@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/horazont/webskat/internal/replay"
+	"github.com/horazont/webskat/internal/skat"
+)
+
+var (
+	interactive = flag.Bool("replay.interactive", false, "step through the game action by action")
+	stopAfter   = flag.Int("replay.stop-after", -1, "stop after this many actions; replay the whole log if negative")
+)
+
+var (
+	errScoreMismatch = errors.New("final scores do not match the log")
+)
+
+func phaseName(phase skat.GamePhase) string {
+	switch phase {
+	case skat.PhaseInit:
+		return "collecting seeds"
+	case skat.PhaseBidding:
+		return "bidding"
+	case skat.PhaseDeclaration:
+		return "declaration"
+	case skat.PhasePlaying:
+		return "playing"
+	case skat.PhaseScored:
+		return "scored"
+	case skat.PhasePassedIn:
+		return "passed in"
+	case skat.PhaseJunkPushing:
+		return "pushing the skat"
+	case skat.PhaseExchange:
+		return "exchanging cards"
+	case skat.PhaseAudit:
+		return "revealing keys"
+	default:
+		return fmt.Sprintf("unknown phase %d", phase)
+	}
+}
+
+func playerName(player int) string {
+	if player == skat.PlayerNone {
+		return "Dealer"
+	}
+	return fmt.Sprintf("Player %d", player)
+}
+
+func renderRecord(step int, record *replay.LogRecord) {
+	spec, err := json.Marshal(record.Action)
+	if err != nil {
+		spec = []byte("?")
+	}
+	fmt.Printf(
+		"#%d %s  %s: %s %s\n",
+		step+1,
+		record.Timestamp.Format("15:04:05"),
+		playerName(record.Player),
+		record.Action.Kind(),
+		spec,
+	)
+}
+
+func renderGame(g *skat.GameState) {
+	gs := g.BlindedForDealer()
+	fmt.Printf("Phase: %s\n", phaseName(gs.Phase))
+	if gs.Phase == skat.PhaseInit {
+		return
+	}
+
+	if gs.Phase == skat.PhaseBidding {
+		fmt.Printf("Last call: %d\n", gs.LastBiddingCall)
+	}
+	if gs.Declarer != skat.PlayerNone {
+		fmt.Printf("Declarer: %s, bid %d\n", playerName(gs.Declarer), gs.LastBiddingCall)
+	}
+	if gs.GameType != skat.InvalidGameType {
+		fmt.Printf("Game: %s", gs.GameType.Pretty())
+		if gs.AnnouncedModifiers != skat.NoGameModifiers {
+			fmt.Printf(" %s", gs.AnnouncedModifiers.Pretty())
+		}
+		fmt.Printf("\n")
+	}
+	if gs.Phase == skat.PhaseScored || gs.Phase == skat.PhasePassedIn {
+		hands, skatCards := g.Dealt()
+		for i, hand := range hands {
+			fmt.Printf("  %s started with: %s\n", playerName(i), hand.Pretty())
+		}
+		fmt.Printf("  Skat: %s\n", skatCards.Pretty())
+	} else {
+		for i := 0; i < 3; i = i + 1 {
+			marker := " "
+			if gs.CurrentPlayer == i {
+				marker = ">"
+			}
+			if d := g.SealedDeal(); d != nil {
+				fmt.Printf("%s %s: %d sealed cards\n", marker, playerName(i), len(d.Hands[i]))
+				continue
+			}
+			fmt.Printf("%s %s: %s\n", marker, playerName(i), g.GetHand(i).Pretty())
+		}
+		if skatCards := g.GetSkat(); len(skatCards) > 0 {
+			fmt.Printf("  Skat: %s\n", skatCards.Pretty())
+		}
+	}
+	if len(gs.Table) > 0 {
+		fmt.Printf("  Table: %s\n", gs.Table.Pretty())
+	}
+	for i, trick := range gs.Tricks {
+		fmt.Printf("  Trick %2d:", i+1)
+		for j, card := range trick.Cards {
+			marker := " "
+			if trick.Players[j] == trick.Winner {
+				marker = "*"
+			}
+			fmt.Printf(" %s%s(%d)", marker, card.Pretty(), trick.Players[j])
+		}
+		fmt.Printf("\n")
+	}
+	if gs.Phase == skat.PhaseScored {
+		fmt.Printf("Game value: %d", gs.FinalGameValue)
+		if gs.LossReason != "" {
+			fmt.Printf(" (lost: %s)", gs.LossReason)
+		}
+		fmt.Printf("\n")
+		for i := 0; i < 3; i = i + 1 {
+			fmt.Printf("  %s: %d\n", playerName(i), g.GetScore(i))
+		}
+	}
+}
+
+// Step through the log; returns false if the user quit early
+func stepInteractively(r *replay.Replayer, records []*replay.LogRecord) (bool, error) {
+	in := bufio.NewScanner(os.Stdin)
+	running := false
+	for !r.Done() {
+		if *stopAfter >= 0 && r.Position() >= *stopAfter {
+			break
+		}
+		if !running {
+			fmt.Printf("[enter] next, [r]un to the end, [q]uit: ")
+			if !in.Scan() {
+				return false, in.Err()
+			}
+			switch strings.TrimSpace(in.Text()) {
+			case "q":
+				return false, nil
+			case "r":
+				running = true
+			}
+		}
+
+		renderRecord(r.Position(), records[r.Position()])
+		if err := r.Step(); err != nil {
+			return true, err
+		}
+		if !running {
+			renderGame(r.Game())
+			fmt.Printf("\n")
+		}
+	}
+	return true, nil
+}
+
+// Check the seeds (or keys) of a finished game against their commitments and
+// the deal they produce against the hands recorded in the log
+//
+// The player seeds have already been checked against their commitments while
+// replaying the log.
+func verifyDeal(g *skat.GameState, result *replay.LogResult) error {
+	gs := g.BlindedForDealer()
+	gs.Reveal = &skat.GameReveal{
+		Hands: result.Hands[:],
+		Skat:  result.Skat,
+	}
+	if gs.SealedDeal != nil {
+		return skat.VerifySealedDeal(gs, skat.PlayerNone, nil)
+	}
+	return skat.VerifyDeal(gs, skat.PlayerNone, nil)
+}
+
+func verifyResult(g *skat.GameState, result *replay.LogResult) error {
+	if g.Phase() != result.Phase {
+		return errScoreMismatch
+	}
+	for i, score := range result.Scores {
+		if g.GetScore(i) != score {
+			return errScoreMismatch
+		}
+	}
+	return nil
+}
+
+func main() {
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatal(err)
+	}
+	zap.ReplaceGlobals(logger)
+
+	flag.Parse()
+
+	sl := zap.S()
+
+	if flag.NArg() != 1 {
+		sl.Fatalw("usage: skat-replay [flags] LOGFILE")
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		sl.Fatalw("failed to open log",
+			"err", err,
+		)
+	}
+	gameLog, err := replay.ReadLog(f)
+	f.Close()
+	if err != nil {
+		sl.Fatalw("failed to read log",
+			"err", err,
+		)
+	}
+
+	r, err := replay.NewReplayer(gameLog)
+	if err != nil {
+		sl.Fatalw("failed to set up the game from the log header",
+			"err", err,
+		)
+	}
+
+	if *interactive {
+		var complete bool
+		complete, err = stepInteractively(r, gameLog.Records)
+		if !complete {
+			return
+		}
+	} else {
+		err = r.Run(*stopAfter)
+	}
+
+	failed := false
+	if err != nil {
+		fmt.Printf("actions: FAILED: %s\n", err)
+		failed = true
+	} else {
+		fmt.Printf("actions: ok (%d of %d applied)\n", r.Position(), len(gameLog.Records))
+	}
+
+	g := r.Game()
+	if gameLog.Result == nil || (g.Phase() != skat.PhaseScored && g.Phase() != skat.PhasePassedIn) {
+		fmt.Printf("deal: not checked, the game has not been scored\n")
+	} else if g.Phase() == skat.PhasePassedIn && g.SealedDeal() != nil {
+		fmt.Printf("deal: not checked, the sealed deal was passed in\n")
+	} else if d := g.SealedDeal(); d != nil && !d.Audited() {
+		fmt.Printf("deal: not checked, player %d withheld their key\n", d.Cheater)
+	} else if err := verifyDeal(g, gameLog.Result); err != nil {
+		fmt.Printf("deal: FAILED: %s\n", err)
+		failed = true
+	} else {
+		fmt.Printf("deal: ok\n")
+	}
+
+	if gameLog.Result == nil {
+		fmt.Printf("result: game was not finished\n")
+	} else if !r.Done() {
+		fmt.Printf("result: not checked, the replay did not reach the end\n")
+	} else if err := verifyResult(g, gameLog.Result); err != nil {
+		fmt.Printf("result: FAILED: %s\n", err)
+		failed = true
+	} else {
+		fmt.Printf("result: ok\n")
+	}
+
+	if !*interactive {
+		fmt.Printf("\n")
+		renderGame(g)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
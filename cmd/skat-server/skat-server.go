@@ -6,22 +6,68 @@ import (
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"encoding/pem"
 	"flag"
 	"log"
 	"math/big"
+	"os"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/horazont/webskat/internal/frontend/singleuser"
+	"github.com/horazont/webskat/internal/skat"
 )
 
 var (
 	serverListenAddress = flag.String("server.listen-address", "127.0.0.1:5023", "")
 	serverPassword      = flag.String("server.password", "foobar2342", "")
+	serverStateFile     = flag.String("server.state-file", "", "checkpoint the session to this file and resume from it on startup")
+	serverLogDirectory  = flag.String("server.log-dir", "", "record each game to a replay log in this directory")
+	serverKeyTimeout    = flag.Duration("server.key-reveal-timeout", singleuser.DefaultKeyRevealTimeout, "with a sealed deal, treat a player who has not revealed their key this long after the game as a cheater")
+	tablePlayers        = flag.Int("table.players", 3, "number of players at the table (3 or 4)")
+	rulesFile           = flag.String("rules.file", "", "JSON file with the house rules; omitted settings follow the standard rules")
+	bockEnabled         = flag.Bool("bock.enabled", false, "play Bock rounds")
+	bockRoundLength     = flag.Int("bock.round-length", 3, "number of games in a Bock round")
+	bockMultiplier      = flag.Int("bock.multiplier", 2, "score multiplier during a Bock round")
+	bockJunkRounds      = flag.Bool("bock.junk-rounds", false, "follow each Bock round with a Junk round")
+	rulesDealing        = flag.String("rules.dealing", "seeded", "how the cards are dealt: seeded, or mental_poker to keep the hands from the server")
 )
 
+// Load the house rules from the rules file, if any, and apply the dealing and
+// bock flags which were given explicitly
+func loadRules() (*skat.RuleSet, error) {
+	rules := skat.StandardRuleSet()
+	if *rulesFile != "" {
+		f, err := os.Open(*rulesFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if err := json.NewDecoder(f).Decode(rules); err != nil {
+			return nil, err
+		}
+	}
+
+	flag.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "rules.dealing":
+			rules.Dealing = skat.DealingMode(*rulesDealing)
+		case "bock.enabled":
+			rules.Bock.Enabled = *bockEnabled
+		case "bock.round-length":
+			rules.Bock.RoundLength = *bockRoundLength
+		case "bock.multiplier":
+			rules.Bock.Multiplier = *bockMultiplier
+		case "bock.junk-rounds":
+			rules.Bock.JunkRounds = *bockJunkRounds
+		}
+	})
+
+	return rules, rules.Validate()
+}
+
 func generateSelfSigned() tls.Certificate {
 	key, err := rsa.GenerateKey(rand.Reader, 1024)
 	if err != nil {
@@ -75,8 +121,20 @@ func main() {
 		"listenAddress", *serverListenAddress,
 	)
 
+	rules, err := loadRules()
+	if err != nil {
+		sl.Fatalw("failed to load house rules",
+			"err", err,
+		)
+	}
+
 	gs, err := singleuser.NewGameServer(singleuser.GameServerConfig{
-		ServerPassword: *serverPassword,
+		ServerPassword:   *serverPassword,
+		Players:          *tablePlayers,
+		Rules:            rules,
+		StateFile:        *serverStateFile,
+		LogDirectory:     *serverLogDirectory,
+		KeyRevealTimeout: *serverKeyTimeout,
 	}, sl.With("component", "game_server"))
 	if err != nil {
 		sl.Fatalw("failed to initialize game",
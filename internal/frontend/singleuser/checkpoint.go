@@ -0,0 +1,161 @@
+package singleuser
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+const (
+	checkpointVersion = 1
+)
+
+var (
+	ErrCheckpointVersion  = errors.New("unsupported checkpoint version")
+	ErrCheckpointMismatch = errors.New("checkpoint does not match the table configuration")
+)
+
+type checkpointClient struct {
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret"`
+}
+
+// Everything needed to resume a session after a restart
+type checkpoint struct {
+	Version int `json:"version"`
+	Seats   int `json:"seats"`
+	// Clients in the order of their seats
+	Clients          []checkpointClient `json:"clients"`
+	Game             *skat.GameSnapshot `json:"game"`
+	PlayerOffset     int                `json:"playerOffset"`
+	LastGame         *skat.GameSnapshot `json:"lastGame,omitempty"`
+	LastPlayerOffset int                `json:"lastPlayerOffset"`
+	Bock             skat.BockState     `json:"bock"`
+	ScoreSheet       *skat.ScoreSheet   `json:"scoreSheet"`
+	// Replay log of the current game, if any
+	LogFile string `json:"logFile,omitempty"`
+}
+
+// Write the session to the state file, replacing the previous checkpoint
+//
+// The checkpoint holds the client secrets, the server seed and the hands of
+// the current game, so it is only readable by the user running the server.
+// Must be called with the state lock held.
+func (s *GameServer) writeCheckpoint() error {
+	if s.stateFile == "" {
+		return nil
+	}
+
+	cp := &checkpoint{
+		Version:          checkpointVersion,
+		Seats:            s.seats,
+		Clients:          make([]checkpointClient, len(s.playerReverseMap)),
+		Game:             s.currentGame.Snapshot(),
+		PlayerOffset:     s.currentPlayerOffset,
+		LastPlayerOffset: s.lastPlayerOffset,
+		Bock:             s.bock.State(),
+		ScoreSheet:       s.scoreSheet,
+		LogFile:          s.logFile,
+	}
+	for i, clientID := range s.playerReverseMap {
+		cp.Clients[i] = checkpointClient{
+			ClientID:     clientID,
+			ClientSecret: s.clients[clientID].clientSecret,
+		}
+	}
+	if s.lastGame != nil {
+		cp.LastGame = s.lastGame.Snapshot()
+	}
+
+	tmpfile, err := ioutil.TempFile(filepath.Dir(s.stateFile), "."+filepath.Base(s.stateFile)+".*")
+	if err != nil {
+		return err
+	}
+	tmpfileName := tmpfile.Name()
+	defer tmpfile.Close()
+	defer os.Remove(tmpfileName)
+
+	err = tmpfile.Chmod(0600)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(tmpfile)
+	err = enc.Encode(cp)
+	if err != nil {
+		return err
+	}
+	err = tmpfile.Sync()
+	if err != nil {
+		return err
+	}
+	err = tmpfile.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpfileName, s.stateFile)
+}
+
+// Resume the session from the state file
+//
+// Returns false if there is no checkpoint yet. The clients have to log in
+// again with their previous secret to take their seats.
+func (s *GameServer) readCheckpoint() (bool, error) {
+	f, err := os.Open(s.stateFile)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	cp := &checkpoint{}
+	if err := json.NewDecoder(f).Decode(cp); err != nil {
+		return false, err
+	}
+	if cp.Version != checkpointVersion {
+		return false, ErrCheckpointVersion
+	}
+	if cp.Seats != s.seats || len(cp.Clients) > s.seats || cp.Game == nil || cp.ScoreSheet == nil || cp.ScoreSheet.Seats() != s.seats {
+		return false, ErrCheckpointMismatch
+	}
+	if cp.PlayerOffset < 0 || cp.PlayerOffset >= s.seats || cp.LastPlayerOffset < 0 || cp.LastPlayerOffset >= s.seats {
+		return false, ErrCheckpointMismatch
+	}
+
+	game, err := skat.RestoreGame(cp.Game)
+	if err != nil {
+		return false, err
+	}
+	var lastGame *skat.GameState
+	if cp.LastGame != nil {
+		lastGame, err = skat.RestoreGame(cp.LastGame)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	for i, client := range cp.Clients {
+		if _, ok := s.clients[client.ClientID]; ok {
+			return false, ErrCheckpointMismatch
+		}
+		s.clients[client.ClientID] = &gameClientConn{
+			clientSecret: client.ClientSecret,
+			playerIndex:  i,
+		}
+		s.playerReverseMap = append(s.playerReverseMap, client.ClientID)
+	}
+	s.currentGame = game
+	s.currentPlayerOffset = cp.PlayerOffset
+	s.lastGame = lastGame
+	s.lastPlayerOffset = cp.LastPlayerOffset
+	s.bock = skat.RestoreBockScheduler(s.rules.Bock, cp.Bock)
+	s.scoreSheet = cp.ScoreSheet
+	s.logFile = cp.LogFile
+	return true, nil
+}
@@ -0,0 +1,80 @@
+package singleuser
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+func testNewCheckpointServer(t *testing.T, stateFile string) (*GameServer, error) {
+	return NewGameServer(GameServerConfig{StateFile: stateFile}, zap.NewNop().Sugar())
+}
+
+// Create a server with three seated clients and checkpoint it
+func testGetCheckpointedServer(t *testing.T, stateFile string) *GameServer {
+	s, err := testNewCheckpointServer(t, stateFile)
+	assert.Nil(t, err)
+	for i, clientID := range []string{"alice", "bob", "carol"} {
+		s.clients[clientID] = &gameClientConn{
+			clientSecret: clientID + "-secret",
+			playerIndex:  i,
+		}
+		s.playerReverseMap = append(s.playerReverseMap, clientID)
+	}
+	assert.Nil(t, s.currentGame.CommitSeed(skat.PlayerInitialForehand, skat.CommitToSeed([]byte{1})))
+	assert.Nil(t, s.writeCheckpoint())
+	return s
+}
+
+func TestCheckpoint(t *testing.T) {
+	t.Run("starts a new session without a checkpoint", func(t *testing.T) {
+		s, err := testNewCheckpointServer(t, filepath.Join(t.TempDir(), "state.json"))
+		assert.Nil(t, err)
+		assert.Equal(t, skat.PhaseInit, s.currentGame.Phase())
+		assert.Equal(t, 0, len(s.playerReverseMap))
+	})
+
+	t.Run("resumes the session", func(t *testing.T) {
+		stateFile := filepath.Join(t.TempDir(), "state.json")
+		s := testGetCheckpointedServer(t, stateFile)
+
+		resumed, err := testNewCheckpointServer(t, stateFile)
+		assert.Nil(t, err)
+		assert.Equal(t, s.playerReverseMap, resumed.playerReverseMap)
+		for clientID, client := range s.clients {
+			assert.Equal(t, client.clientSecret, resumed.clients[clientID].clientSecret)
+			assert.Equal(t, client.playerIndex, resumed.clients[clientID].playerIndex)
+		}
+		assert.Equal(t, s.currentGame.Snapshot(), resumed.currentGame.Snapshot())
+		assert.Equal(t, s.currentGame.ServerSeed(), resumed.currentGame.ServerSeed())
+	})
+
+	t.Run("is only readable by the server", func(t *testing.T) {
+		stateFile := filepath.Join(t.TempDir(), "state.json")
+		testGetCheckpointedServer(t, stateFile)
+		info, err := os.Stat(stateFile)
+		assert.Nil(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("rejects a corrupt checkpoint", func(t *testing.T) {
+		stateFile := filepath.Join(t.TempDir(), "state.json")
+		testGetCheckpointedServer(t, stateFile)
+		assert.Nil(t, ioutil.WriteFile(stateFile, []byte("{\"version\": 1, \"seats\""), 0600))
+		_, err := testNewCheckpointServer(t, stateFile)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("rejects a checkpoint for another table", func(t *testing.T) {
+		stateFile := filepath.Join(t.TempDir(), "state.json")
+		testGetCheckpointedServer(t, stateFile)
+		_, err := NewGameServer(GameServerConfig{StateFile: stateFile, Players: 4}, zap.NewNop().Sugar())
+		assert.Equal(t, ErrCheckpointMismatch, err)
+	})
+}
@@ -1,7 +1,10 @@
 package singleuser
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"sync"
 
 	"go.uber.org/zap"
 
@@ -9,19 +12,53 @@ import (
 	"github.com/horazont/webskat/internal/skat"
 )
 
+var (
+	ErrServerSeedMismatch = errors.New("server seed does not match the commitment of the server")
+	ErrCommitmentChanged  = errors.New("the server changed its commitment after this client committed to its seed")
+	ErrNoSeedCommitted    = errors.New("no seed has been committed to")
+	ErrDealNotObserved    = errors.New("the deal of this game has not been observed")
+	ErrOwnSeedMismatch    = errors.New("the seed revealed for this client is not the one it committed to")
+	ErrKeyLost            = errors.New("the key of this client for the sealed deal is not known")
+)
+
 type GameClient struct {
 	l        *zap.SugaredLogger
 	conn     MessageEndpoint
 	clientID string
 	quit     chan struct{}
 
-	states       chan ClientState
-	memoizedSeed []byte
+	states chan ClientState
+
+	// memoizedSeed and committedAgainst, the commitment of the server at that
+	// time, are written by CommitSeed; the other fields describe the deal of
+	// the game identified by the commitment of the server and are written by
+	// the receiving loop
+	dealLock         sync.Mutex
+	memoizedSeed     []byte
+	committedAgainst []byte
+	dealCommitment   []byte
+	dealtSeed        []byte
+	dealtAgainst     []byte
+	dealtHand        skat.CardSet
+
+	// sealedKey is the key of this client for the sealed deal of the game
+	// identified by sealedKeyFor; sealedDeal is the most recently received
+	// sealed deal, in which this client is sealedPlayer and has the key
+	// sealedDealKey, if any
+	sealedKey     *skat.MentalPokerKey
+	sealedKeyFor  []byte
+	sealedDeal    *skat.SealedDeal
+	sealedDealKey *skat.MentalPokerKey
+	sealedPlayer  int
 }
 
 type ClientState struct {
 	PlayerIndex int
 	GameState   *skat.BlindedGameState
+	Bock        *skat.BockState
+	Seat        int
+	ScoreSheet  *skat.ScoreSheet
+	LastGame    *LastGameInfo
 }
 
 func NewGameClient(l *zap.SugaredLogger, ctx context.Context, conn MessageEndpoint) (*GameClient, error) {
@@ -55,6 +92,8 @@ func (c *GameClient) handleMessage(msgH MessageHandle) {
 			c.l.Debugw("received state update",
 				"state", stateMsg.GameState,
 			)
+			c.unseal(stateMsg.YourPlayerIndex, stateMsg.GameState)
+			c.observeDeal(stateMsg.YourPlayerIndex, stateMsg.GameState)
 			if len(c.states) >= cap(c.states) {
 				// drop one state from the queue in case the recipient is
 				// overloaded
@@ -69,6 +108,10 @@ func (c *GameClient) handleMessage(msgH MessageHandle) {
 			c.states <- ClientState{
 				PlayerIndex: stateMsg.YourPlayerIndex,
 				GameState:   stateMsg.GameState,
+				Bock:        stateMsg.Bock,
+				Seat:        stateMsg.YourSeat,
+				ScoreSheet:  stateMsg.ScoreSheet,
+				LastGame:    stateMsg.LastGame,
 			}
 		}
 	case MsgPing:
@@ -104,6 +147,213 @@ func (c *GameClient) handleMessage(msgH MessageHandle) {
 	}
 }
 
+// Test whether the hand in the state is still the hand as dealt
+func isFreshDeal(gs *skat.BlindedGameState) bool {
+	if len(gs.Hand) != 10 || len(gs.SkatPasses) > 0 {
+		return false
+	}
+	switch gs.Phase {
+	case skat.PhaseBidding:
+		return true
+	case skat.PhaseJunkPushing:
+		return gs.SkatCards == 2
+	case skat.PhasePlaying:
+		// Junk games may start right after dealing
+		if gs.GameType != skat.GameTypeJunk || len(gs.Table) > 0 {
+			return false
+		}
+		for _, player := range gs.Players {
+			if player.Ncards != 10 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// Remember the hand and seed of a game when it is first seen after dealing
+//
+// The hand is only remembered if it has not changed since dealing, e.g. when
+// reconnecting in the middle of a game.
+func (c *GameClient) observeDeal(playerIndex int, gs *skat.BlindedGameState) {
+	if gs == nil || gs.Phase == skat.PhaseInit {
+		return
+	}
+
+	c.dealLock.Lock()
+	defer c.dealLock.Unlock()
+	if bytes.Equal(c.dealCommitment, gs.ServerCommitment) {
+		return
+	}
+	c.dealCommitment = gs.ServerCommitment
+	c.dealtSeed = c.memoizedSeed
+	c.dealtAgainst = c.committedAgainst
+	c.dealtHand = nil
+	if playerIndex == skat.PlayerNone {
+		return
+	}
+	if !isFreshDeal(gs) {
+		c.dealCommitment = nil
+		return
+	}
+	c.dealtHand = gs.Hand.Copy()
+}
+
+// Decrypt the hand of this client from a sealed deal, see
+// skat.BlindedGameState.Unseal
+func (c *GameClient) unseal(playerIndex int, gs *skat.BlindedGameState) {
+	if gs == nil {
+		return
+	}
+
+	c.dealLock.Lock()
+	c.sealedDeal = gs.SealedDeal
+	c.sealedPlayer = playerIndex
+	key := c.sealedKeyLocked(gs)
+	c.sealedDealKey = key
+	c.dealLock.Unlock()
+
+	if key == nil || playerIndex == skat.PlayerNone {
+		return
+	}
+	if err := gs.Unseal(playerIndex, key); err != nil {
+		c.l.Warnw("failed to decrypt the sealed hand",
+			"err", err,
+		)
+	}
+}
+
+// Return the key of this client for the game; dealLock must be held
+func (c *GameClient) sealedKeyLocked(gs *skat.BlindedGameState) *skat.MentalPokerKey {
+	if gs.SealedDeal == nil || !bytes.Equal(c.sealedKeyFor, gs.ServerCommitment) {
+		return nil
+	}
+	return c.sealedKey
+}
+
+// Take the next step of a sealed deal, if it is up to this client
+//
+// This publishes the key of this client, shuffles the deck and strips the
+// encryption from the cards of the other players during the deal, and
+// reveals the key for the audit. Returns true if an action was sent.
+func (c *GameClient) AdvanceSealedDeal(ctx context.Context, st ClientState) (bool, error) {
+	gs := st.GameState
+	d := gs.SealedDeal
+	player := st.PlayerIndex
+	if d == nil || player == skat.PlayerNone {
+		return false, nil
+	}
+
+	c.dealLock.Lock()
+	key := c.sealedKeyLocked(gs)
+	c.dealLock.Unlock()
+
+	if d.PublicKeys[player] == nil {
+		if gs.Phase != skat.PhaseInit {
+			return false, nil
+		}
+		key, err := skat.GenerateMentalPokerKey()
+		if err != nil {
+			return false, err
+		}
+		c.dealLock.Lock()
+		c.sealedKey = key
+		c.sealedKeyFor = gs.ServerCommitment
+		c.dealLock.Unlock()
+		return true, c.sendAction(ctx, replay.PublishKey(key))
+	}
+	if key == nil {
+		return false, ErrKeyLost
+	}
+
+	switch {
+	case gs.Phase == skat.PhaseAudit:
+		if d.SecretKeys[player] != nil {
+			return false, nil
+		}
+		return true, c.sendAction(ctx, replay.RevealKey(key))
+	case gs.Phase != skat.PhaseInit && gs.Phase != skat.PhaseDeclaration:
+		return false, nil
+	case d.NextShuffler() == player:
+		deck := skat.MentalPokerDeck()
+		if d.Shuffled > 0 {
+			for i, slot := range d.Slots {
+				deck[i] = slot.Value
+			}
+		}
+		shuffled, err := key.ShuffleDeck(deck)
+		if err != nil {
+			return false, err
+		}
+		return true, c.sendAction(ctx, &replay.ActionShuffle{Deck: shuffled})
+	case d.NextStripper() == player:
+		strips, err := d.Strip(player, key)
+		if err != nil {
+			return false, err
+		}
+		return true, c.sendAction(ctx, &replay.ActionStrip{Strips: strips})
+	}
+	return false, nil
+}
+
+// Return the most recently received sealed deal with the key of this client
+//
+// Returns a nil deal if the current game is not sealed.
+func (c *GameClient) currentSealedDeal() (*skat.SealedDeal, int, *skat.MentalPokerKey, error) {
+	c.dealLock.Lock()
+	defer c.dealLock.Unlock()
+	if c.sealedDeal == nil {
+		return nil, skat.PlayerNone, nil, nil
+	}
+	if c.sealedDealKey == nil {
+		return nil, skat.PlayerNone, nil, ErrKeyLost
+	}
+	return c.sealedDeal, c.sealedPlayer, c.sealedDealKey, nil
+}
+
+// Check that the deal of a scored or passed-in game matches the revealed seeds
+//
+// The shuffle is recomputed from the seeds and compared against the hand this
+// client was dealt and the revealed hands. Returns ErrDealNotObserved if the
+// client did not see the hand as dealt, ErrCommitmentChanged if the server
+// dealt with another commitment than the one the seed of this client was
+// committed against, ErrOwnSeedMismatch if the seed of this client has been
+// replaced and skat.ErrDealMismatch or skat.ErrSeedMismatch if the deal was
+// not fair.
+func (c *GameClient) VerifyDeal(st ClientState) error {
+	gs := st.GameState
+	c.dealLock.Lock()
+	observed := c.dealCommitment != nil && bytes.Equal(c.dealCommitment, gs.ServerCommitment)
+	seed := c.dealtSeed
+	dealtAgainst := c.dealtAgainst
+	hand := c.dealtHand
+	c.dealLock.Unlock()
+
+	if gs.SealedDeal != nil {
+		if !observed {
+			return ErrDealNotObserved
+		}
+		return skat.VerifySealedDeal(gs, st.PlayerIndex, hand)
+	}
+	if !observed || seed == nil {
+		return ErrDealNotObserved
+	}
+	if !bytes.Equal(dealtAgainst, gs.ServerCommitment) {
+		return ErrCommitmentChanged
+	}
+
+	revealedSeed := gs.DealerSeed
+	if st.PlayerIndex != skat.PlayerNone {
+		revealedSeed = gs.Players[st.PlayerIndex].Seed
+	}
+	if !bytes.Equal(seed, revealedSeed) {
+		return ErrOwnSeedMismatch
+	}
+
+	return skat.VerifyDeal(gs, st.PlayerIndex, hand)
+}
+
 func (c *GameClient) loop() error {
 	for {
 		select {
@@ -228,6 +478,44 @@ func (c *GameClient) sendAction(ctx context.Context, action replay.Action) error
 	}
 }
 
+// Generate a seed and commit to it
+//
+// The seed is kept until it is revealed with RevealSeed, together with the
+// commitment of the server in the state it was committed in.
+func (c *GameClient) CommitSeed(ctx context.Context, gs *skat.BlindedGameState) error {
+	seed, err := skat.GenerateSeed()
+	if err != nil {
+		return err
+	}
+	c.dealLock.Lock()
+	c.memoizedSeed = seed
+	c.committedAgainst = append([]byte(nil), gs.ServerCommitment...)
+	c.dealLock.Unlock()
+	return c.sendAction(ctx, replay.CommitSeed(seed))
+}
+
+// Reveal the seed committed to with CommitSeed
+//
+// The seed is only revealed if the server still has the commitment it had
+// when the seed was committed to and the server seed in the state matches
+// it; otherwise, ErrCommitmentChanged or ErrServerSeedMismatch is returned.
+func (c *GameClient) RevealSeed(ctx context.Context, gs *skat.BlindedGameState) error {
+	c.dealLock.Lock()
+	seed := c.memoizedSeed
+	committedAgainst := c.committedAgainst
+	c.dealLock.Unlock()
+	if seed == nil {
+		return ErrNoSeedCommitted
+	}
+	if !bytes.Equal(committedAgainst, gs.ServerCommitment) {
+		return ErrCommitmentChanged
+	}
+	if !skat.VerifySeedCommitment(gs.ServerSeed, committedAgainst) {
+		return ErrServerSeedMismatch
+	}
+	return c.SetSeed(ctx, seed)
+}
+
 func (c *GameClient) SetSeed(ctx context.Context, seed []byte) error {
 	return c.sendAction(
 		ctx,
@@ -262,7 +550,29 @@ func (c *GameClient) TakeSkat(ctx context.Context) error {
 	)
 }
 
+// Declare a game
+//
+// With a sealed deal, the cards to push are sent as the slots which hold
+// them.
 func (c *GameClient) Declare(ctx context.Context, gameType skat.GameType, announcedModifiers skat.GameModifier, cardsToPush skat.CardSet) error {
+	d, player, key, err := c.currentSealedDeal()
+	if err != nil {
+		return err
+	}
+	if d != nil && len(cardsToPush) > 0 {
+		slots, err := d.FindSlots(player, key, cardsToPush)
+		if err != nil {
+			return err
+		}
+		return c.sendAction(
+			ctx,
+			&replay.ActionDeclare{
+				GameType:          gameType,
+				AnnounceModifiers: announcedModifiers,
+				SlotsToPush:       slots,
+			},
+		)
+	}
 	return c.sendAction(
 		ctx,
 		&replay.ActionDeclare{
@@ -273,11 +583,110 @@ func (c *GameClient) Declare(ctx context.Context, gameType skat.GameType, announ
 	)
 }
 
+// Play a card
+//
+// With a sealed deal, the card is sent with the proof that it is in the hand
+// of this client.
 func (c *GameClient) PlayCard(ctx context.Context, card skat.Card) error {
+	d, player, key, err := c.currentSealedDeal()
+	if err != nil {
+		return err
+	}
+	var reveal *skat.CardReveal
+	if d != nil {
+		reveal, err = d.RevealCard(player, key, card)
+		if err != nil {
+			return err
+		}
+	}
 	return c.sendAction(
 		ctx,
 		&replay.ActionPlayCard{
-			Card: card,
+			Card:   card,
+			Reveal: reveal,
+		},
+	)
+}
+
+func (c *GameClient) Kontra(ctx context.Context) error {
+	return c.sendAction(
+		ctx,
+		&replay.ActionKontra{},
+	)
+}
+
+func (c *GameClient) Re(ctx context.Context) error {
+	return c.sendAction(
+		ctx,
+		&replay.ActionRe{},
+	)
+}
+
+func (c *GameClient) Resign(ctx context.Context) error {
+	return c.sendAction(
+		ctx,
+		&replay.ActionResign{},
+	)
+}
+
+func (c *GameClient) Peek(ctx context.Context, target skat.PeekTarget) error {
+	return c.sendAction(
+		ctx,
+		&replay.ActionPeek{
+			Target: target,
+		},
+	)
+}
+
+func (c *GameClient) Claim(ctx context.Context, tricks int) error {
+	return c.sendAction(
+		ctx,
+		&replay.ActionClaim{
+			Tricks: tricks,
+		},
+	)
+}
+
+func (c *GameClient) ReplyToClaim(ctx context.Context, accept bool) error {
+	return c.sendAction(
+		ctx,
+		&replay.ActionReplyToClaim{
+			Accept: accept,
+		},
+	)
+}
+
+func (c *GameClient) WatchHand(ctx context.Context, player int) error {
+	return c.sendAction(
+		ctx,
+		&replay.ActionWatchHand{
+			Player: player,
+		},
+	)
+}
+
+func (c *GameClient) PushSkatOn(ctx context.Context, cards skat.CardSet) error {
+	return c.sendAction(
+		ctx,
+		&replay.ActionJunkPush{
+			Cards: cards,
+		},
+	)
+}
+
+func (c *GameClient) PassSkatOn(ctx context.Context) error {
+	return c.sendAction(
+		ctx,
+		&replay.ActionJunkPassOn{},
+	)
+}
+
+func (c *GameClient) ExchangeCards(ctx context.Context, give skat.CardSet, take skat.CardSet) error {
+	return c.sendAction(
+		ctx,
+		&replay.ActionExchange{
+			Give: give,
+			Take: take,
 		},
 	)
 }
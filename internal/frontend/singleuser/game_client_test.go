@@ -0,0 +1,161 @@
+package singleuser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+// Apply the actions of a client to a game, as the server would
+type testActionEndpoint struct {
+	g      *skat.GameState
+	player int
+}
+
+func (ep *testActionEndpoint) Request(ctx context.Context, msg Message) (<-chan OptionalMessage, error) {
+	result := make(chan OptionalMessage, 1)
+	action, err := msg.(*ActionMessage).Payload()
+	if err == nil {
+		err = action.Apply(ep.g, ep.player)
+	}
+	if err != nil {
+		result <- OptionalMessage{Msg: NewErrorMessage(400, err.Error())}
+	} else {
+		result <- OptionalMessage{Msg: &AckMessage{}}
+	}
+	return result, nil
+}
+
+func (ep *testActionEndpoint) Reply(ctx context.Context, msg Message) error {
+	return nil
+}
+
+func (ep *testActionEndpoint) OneShot(ctx context.Context, msg Message) error {
+	return nil
+}
+
+func (ep *testActionEndpoint) RecvChannel() <-chan MessageHandle {
+	return nil
+}
+
+func (ep *testActionEndpoint) Close() error {
+	return nil
+}
+
+// Create a client which plays as forehand in g
+func testNewGameClient(t *testing.T, g *skat.GameState) (*GameClient, *testActionEndpoint) {
+	ep := &testActionEndpoint{g: g, player: skat.PlayerInitialForehand}
+	c := &GameClient{
+		l:      zap.NewNop().Sugar(),
+		conn:   ep,
+		quit:   make(chan struct{}, 0),
+		states: make(chan ClientState, 1),
+	}
+	return c, ep
+}
+
+func testNewClientGame(t *testing.T) *skat.GameState {
+	g, err := skat.NewGame(false, skat.LeagueScoreDefinition(), skat.StandardRuleSet())
+	assert.Nil(t, err)
+	return g
+}
+
+// Commit the seed of the client and empty seeds for the other players
+func testCommitClientSeed(t *testing.T, c *GameClient, g *skat.GameState) {
+	assert.Nil(t, c.CommitSeed(context.Background(), g.BlindedForPlayer(skat.PlayerInitialForehand)))
+	assert.Nil(t, g.CommitSeed(skat.PlayerInitialMiddlehand, skat.CommitToSeed([]byte{})))
+	assert.Nil(t, g.CommitSeed(skat.PlayerInitialRearhand, skat.CommitToSeed([]byte{})))
+	assert.True(t, g.SeedsCommitted())
+}
+
+func TestGameClientSeeds(t *testing.T) {
+	t.Run("reveals the seed it committed to", func(t *testing.T) {
+		g := testNewClientGame(t)
+		c, _ := testNewGameClient(t, g)
+		testCommitClientSeed(t, c, g)
+		assert.Nil(t, c.RevealSeed(context.Background(), g.BlindedForPlayer(skat.PlayerInitialForehand)))
+		assert.True(t, g.BlindedForPlayer(skat.PlayerInitialForehand).Players[skat.PlayerInitialForehand].SeedProvided)
+	})
+
+	t.Run("rejects a server seed which does not match", func(t *testing.T) {
+		g := testNewClientGame(t)
+		c, _ := testNewGameClient(t, g)
+		testCommitClientSeed(t, c, g)
+		gs := g.BlindedForPlayer(skat.PlayerInitialForehand)
+		gs.ServerSeed = []byte{1}
+		assert.Equal(t, ErrServerSeedMismatch, c.RevealSeed(context.Background(), gs))
+	})
+
+	t.Run("rejects a commitment swapped after committing", func(t *testing.T) {
+		g := testNewClientGame(t)
+		c, _ := testNewGameClient(t, g)
+		testCommitClientSeed(t, c, g)
+		gs := g.BlindedForPlayer(skat.PlayerInitialForehand)
+		gs.ServerSeed = []byte{1}
+		gs.ServerCommitment = skat.CommitToSeed(gs.ServerSeed)
+		assert.Equal(t, ErrCommitmentChanged, c.RevealSeed(context.Background(), gs))
+		assert.False(t, g.BlindedForPlayer(skat.PlayerInitialForehand).Players[skat.PlayerInitialForehand].SeedProvided)
+	})
+}
+
+// Reveal the remaining seeds and score the game before the first trick
+//
+// The client observes the deal; middlehand wins the bidding and resigns.
+func testScoreClientGame(t *testing.T, c *GameClient, g *skat.GameState) ClientState {
+	assert.Nil(t, g.SetSeed(skat.PlayerInitialMiddlehand, []byte{}))
+	assert.Nil(t, g.SetSeed(skat.PlayerInitialRearhand, []byte{}))
+	assert.Equal(t, skat.PhaseBidding, g.Phase())
+	c.observeDeal(skat.PlayerInitialForehand, g.BlindedForPlayer(skat.PlayerInitialForehand))
+	assert.Nil(t, g.CallBid(skat.PlayerInitialMiddlehand, 18))
+	assert.Nil(t, g.RespondToBid(skat.PlayerInitialForehand, false))
+	assert.Nil(t, g.CallBid(skat.PlayerInitialRearhand, skat.BidPass))
+	assert.Nil(t, g.Resign(skat.PlayerInitialMiddlehand))
+	assert.Equal(t, skat.PhaseScored, g.Phase())
+	return ClientState{
+		PlayerIndex: skat.PlayerInitialForehand,
+		GameState:   g.BlindedForPlayer(skat.PlayerInitialForehand),
+	}
+}
+
+func TestGameClientVerifyDeal(t *testing.T) {
+	t.Run("accepts a fair deal", func(t *testing.T) {
+		g := testNewClientGame(t)
+		c, _ := testNewGameClient(t, g)
+		testCommitClientSeed(t, c, g)
+		assert.Nil(t, c.RevealSeed(context.Background(), g.BlindedForPlayer(skat.PlayerInitialForehand)))
+		st := testScoreClientGame(t, c, g)
+		assert.Nil(t, c.VerifyDeal(st))
+	})
+
+	t.Run("rejects a deal the client did not observe", func(t *testing.T) {
+		g := testNewClientGame(t)
+		c, _ := testNewGameClient(t, g)
+		testCommitClientSeed(t, c, g)
+		assert.Nil(t, c.RevealSeed(context.Background(), g.BlindedForPlayer(skat.PlayerInitialForehand)))
+		st := testScoreClientGame(t, c, g)
+		other, _ := testNewGameClient(t, g)
+		assert.Equal(t, ErrDealNotObserved, other.VerifyDeal(st))
+	})
+
+	t.Run("rejects a seed swapped after committing", func(t *testing.T) {
+		g := testNewClientGame(t)
+		c, _ := testNewGameClient(t, g)
+		testCommitClientSeed(t, c, g)
+
+		// the server deals another game with a seed of its choice, which it
+		// reveals correctly, and the same player seeds
+		swapped := testNewClientGame(t)
+		assert.NotEqual(t, g.ServerSeed(), swapped.ServerSeed())
+		assert.Nil(t, swapped.CommitSeed(skat.PlayerInitialForehand, skat.CommitToSeed(c.memoizedSeed)))
+		assert.Nil(t, swapped.CommitSeed(skat.PlayerInitialMiddlehand, skat.CommitToSeed([]byte{})))
+		assert.Nil(t, swapped.CommitSeed(skat.PlayerInitialRearhand, skat.CommitToSeed([]byte{})))
+		assert.Nil(t, swapped.SetSeed(skat.PlayerInitialForehand, c.memoizedSeed))
+		st := testScoreClientGame(t, c, swapped)
+		assert.Nil(t, skat.VerifyDeal(st.GameState, st.PlayerIndex, st.GameState.Reveal.Hands[st.PlayerIndex]))
+		assert.Equal(t, ErrCommitmentChanged, c.VerifyDeal(st))
+	})
+}
@@ -0,0 +1,100 @@
+package singleuser
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/horazont/webskat/internal/replay"
+)
+
+// Start a new replay log for the current game
+//
+// Must be called with the state lock held. Does nothing if no log directory
+// has been configured.
+func (s *GameServer) openGameLog() error {
+	if s.logDirectory == "" {
+		return nil
+	}
+	s.closeGameLog()
+
+	prefix := "game-" + time.Now().UTC().Format("20060102T150405") + "-"
+	f, err := ioutil.TempFile(s.logDirectory, prefix+"*.jsonl")
+	if err != nil {
+		return err
+	}
+	w := replay.NewLogWriter(f)
+	if err := w.WriteHeader(replay.NewLogHeader(s.currentGame)); err != nil {
+		f.Close()
+		return err
+	}
+
+	s.logFile = f.Name()
+	s.log = f
+	s.logWriter = w
+	s.l.Debugw("started replay log",
+		"logFile", s.logFile,
+	)
+	return nil
+}
+
+// Continue the replay log of a game resumed from a checkpoint
+func (s *GameServer) reopenGameLog() error {
+	f, err := os.OpenFile(s.logFile, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	s.log = f
+	s.logWriter = replay.NewLogWriter(f)
+	return nil
+}
+
+func (s *GameServer) closeGameLog() {
+	if s.log == nil {
+		return
+	}
+	if err := s.log.Close(); err != nil {
+		s.l.Warnw("failed to close replay log",
+			"logFile", s.logFile,
+			"err", err,
+		)
+	}
+	s.log = nil
+	s.logWriter = nil
+	s.logFile = ""
+}
+
+// Append an applied action to the replay log, logging failures
+//
+// A failed write does not affect the running game.
+func (s *GameServer) recordAction(playerIndex int, action replay.Action) {
+	if s.logWriter == nil {
+		return
+	}
+	record := &replay.LogRecord{
+		Player:    playerIndex,
+		Timestamp: time.Now().UTC(),
+		Action:    action,
+	}
+	if err := s.logWriter.Append(record); err != nil {
+		s.l.Errorw("failed to record action",
+			"logFile", s.logFile,
+			"player", playerIndex,
+			"action", action.Kind(),
+			"err", err,
+		)
+	}
+}
+
+// Conclude the replay log with the outcome of the current game
+func (s *GameServer) finishGameLog() {
+	if s.logWriter == nil {
+		return
+	}
+	if err := s.logWriter.Finish(replay.NewLogResult(s.currentGame)); err != nil {
+		s.l.Errorw("failed to record game result",
+			"logFile", s.logFile,
+			"err", err,
+		)
+	}
+}
@@ -3,8 +3,10 @@ package singleuser
 import (
 	"context"
 	"errors"
+	"os"
 	"reflect"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -12,8 +14,15 @@ import (
 	"github.com/horazont/webskat/internal/skat"
 )
 
+const (
+	// See GameServerConfig.KeyRevealTimeout
+	DefaultKeyRevealTimeout = 2 * time.Minute
+)
+
 var (
-	ErrPlayerNotFound = errors.New("player not found")
+	ErrPlayerNotFound     = errors.New("player not found")
+	ErrInvalidPlayerCount = errors.New("a table has three or four players")
+	ErrServerOnlyAction   = errors.New("action is only applied by the server")
 )
 
 type gameClientConn struct {
@@ -30,30 +39,171 @@ type GameServer struct {
 	wakeup           chan struct{}
 	quit             chan struct{}
 
-	serverPassword      string
+	serverPassword string
+	// number of seats at the table; with four seats, the dealer sits out
+	seats               int
+	rules               *skat.RuleSet
 	currentGame         *skat.GameState
 	currentPlayerOffset int
+	bock                *skat.BockScheduler
+	scoreSheet          *skat.ScoreSheet
+
+	// the previous game of the session and the offset it was played with
+	lastGame         *skat.GameState
+	lastPlayerOffset int
+
+	// checkpoint file, see GameServerConfig.StateFile
+	stateFile string
+
+	// replay log of the current game, see GameServerConfig.LogDirectory
+	logDirectory string
+	logFile      string
+	log          *os.File
+	logWriter    *replay.LogWriter
+
+	// see GameServerConfig.KeyRevealTimeout
+	keyRevealTimeout time.Duration
+	// the game for which the deadline of the audit has been started
+	auditGame *skat.GameState
 }
 
 type GameServerConfig struct {
 	ServerPassword string
+	// 3 or 4; defaults to 3 if zero
+	Players int
+	// House rules; the standard rules are used if nil
+	Rules *skat.RuleSet
+	// The session is checkpointed to this file after each action and resumed
+	// from it on startup; no checkpoints are written if empty. The checkpoint
+	// holds the secrets of the session and is only readable by the server.
+	StateFile string
+	// Each game is recorded to a replay log in this directory; no logs are
+	// written if empty
+	LogDirectory string
+	// Once a game with a sealed deal is audited, the first player who has not
+	// revealed their key this long after is treated as a cheater; defaults
+	// to DefaultKeyRevealTimeout if zero
+	KeyRevealTimeout time.Duration
 }
 
 func NewGameServer(cfg GameServerConfig, l *zap.SugaredLogger) (*GameServer, error) {
-	game, err := skat.NewGame(false, skat.StandardScoreDefinition())
-	if err != nil {
+	seats := cfg.Players
+	if seats == 0 {
+		seats = 3
+	}
+	if seats != 3 && seats != 4 {
+		return nil, ErrInvalidPlayerCount
+	}
+
+	rules := cfg.Rules
+	if rules == nil {
+		rules = skat.StandardRuleSet()
+	}
+	keyRevealTimeout := cfg.KeyRevealTimeout
+	if keyRevealTimeout == 0 {
+		keyRevealTimeout = DefaultKeyRevealTimeout
+	}
+	if err := rules.Validate(); err != nil {
 		return nil, err
 	}
 
-	return &GameServer{
+	result := &GameServer{
 		l:                l,
 		clients:          make(map[string]*gameClientConn),
 		playerReverseMap: make([]string, 0),
 		wakeup:           make(chan struct{}, 1),
 		quit:             make(chan struct{}, 0),
 		serverPassword:   cfg.ServerPassword,
-		currentGame:      game,
-	}, nil
+		seats:            seats,
+		rules:            rules,
+		bock:             skat.NewBockScheduler(rules.Bock),
+		scoreSheet:       skat.NewScoreSheet(seats),
+		stateFile:        cfg.StateFile,
+		logDirectory:     cfg.LogDirectory,
+		keyRevealTimeout: keyRevealTimeout,
+	}
+
+	if result.stateFile != "" {
+		resumed, err := result.readCheckpoint()
+		if err != nil {
+			return nil, err
+		}
+		if resumed {
+			l.Infow("resumed session from checkpoint",
+				"stateFile", result.stateFile,
+				"phase", result.currentGame.Phase(),
+			)
+			if result.logDirectory != "" && result.logFile != "" {
+				if err := result.reopenGameLog(); err != nil {
+					return nil, err
+				}
+			} else if result.logDirectory != "" {
+				l.Warnw("checkpoint has no replay log, the current game is not recorded")
+			}
+			result.startAuditDeadline()
+			return result, nil
+		}
+	}
+
+	game, err := result.newGame()
+	if err != nil {
+		return nil, err
+	}
+	result.currentGame = game
+	if err := result.openGameLog(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *GameServer) newGame() (*skat.GameState, error) {
+	game, err := skat.NewGame(s.seats == 4, skat.StandardScoreDefinition(), s.rules)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.bock.Next().ApplyTo(game); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// Book the finished current game and deal the next one
+//
+// The player after the current forehand becomes forehand of the next game.
+// This also applies if all players passed in the current game; the next game
+// is then dealt with fresh seeds.
+func (s *GameServer) nextGame() error {
+	seats := [3]int{}
+	for i := range seats {
+		seats[i] = s.playerToSeat(i)
+	}
+	if err := s.scoreSheet.Record(s.currentGame, seats); err != nil {
+		return err
+	}
+	if s.currentGame.Phase() == skat.PhaseScored {
+		if err := s.bock.Observe(s.currentGame); err != nil {
+			s.l.Warnw("failed to update bock schedule",
+				"err", err,
+			)
+		}
+	}
+
+	game, err := s.newGame()
+	if err != nil {
+		return err
+	}
+	s.lastGame = s.currentGame
+	s.lastPlayerOffset = s.currentPlayerOffset
+	s.currentGame = game
+	s.rotateSeats()
+	if err := s.openGameLog(); err != nil {
+		s.l.Errorw("failed to start replay log",
+			"err", err,
+		)
+	}
+	return nil
 }
 
 func (s *GameServer) getValidEndpoints() ([]string, []MessageEndpoint) {
@@ -176,48 +326,168 @@ func (s *GameServer) getNextMessage() (string, MessageHandle, MessageEndpoint) {
 	}
 }
 
-func (s *GameServer) clientToPlayer(clientID string) int {
+// Map a client to its player index in the current game
+//
+// Seats are numbered in the order in which the clients joined. The seat
+// which is forehand in the current game is mapped to player 0 and so on. With
+// four seats, the seat following rearhand belongs to the dealer, who sits out
+// the game and is mapped to skat.PlayerNone.
+func (s *GameServer) clientToPlayer(clientID string) (int, error) {
 	clientInfo, ok := s.clients[clientID]
 	if !ok {
-		return skat.PlayerNone
+		return skat.PlayerNone, ErrPlayerNotFound
 	}
 
-	absoluteIndex := clientInfo.playerIndex
-	// TODO: support dealer round by adjusting 3 to 4 if there is a dealer
-	// round and then aliasing the dealer somehow. *shrug*
-	relativeIndex := (s.currentPlayerOffset + absoluteIndex) % 3
+	return s.seatToPlayer(clientInfo.playerIndex, s.currentPlayerOffset), nil
+}
 
+func (s *GameServer) seatToPlayer(absoluteIndex int, offset int) int {
+	relativeIndex := (offset + absoluteIndex) % s.seats
+	if relativeIndex >= 3 {
+		return skat.PlayerNone
+	}
 	return relativeIndex
 }
 
+func (s *GameServer) playerToSeat(relativeIndex int) int {
+	if relativeIndex == skat.PlayerNone {
+		relativeIndex = 3
+	}
+	return (relativeIndex - s.currentPlayerOffset + s.seats) % s.seats
+}
+
+// Map a player index of the current game to the client in that seat
+//
+// skat.PlayerNone maps to the dealer, if any. Returns an empty string if the
+// seat has not been taken yet.
 func (s *GameServer) playerToClient(relativeIndex int) string {
-	// TODO: support dealer round by adjusting 3 to 4 if there is a dealer
-	// round and then aliasing the dealer somehow. *shrug*
-	absoluteIndex := (relativeIndex - s.currentPlayerOffset + 3) % 3
+	if relativeIndex == skat.PlayerNone && s.seats < 4 {
+		return ""
+	}
+	absoluteIndex := s.playerToSeat(relativeIndex)
+	if absoluteIndex >= len(s.playerReverseMap) {
+		return ""
+	}
 
 	clientID := s.playerReverseMap[absoluteIndex]
 	return clientID
 }
 
+// Move all players one seat on, so that the player after the current
+// forehand becomes forehand and, with four seats, the current forehand deals
+func (s *GameServer) rotateSeats() {
+	s.currentPlayerOffset = (s.currentPlayerOffset + s.seats - 1) % s.seats
+}
+
 func (s *GameServer) processAction(clientID string, action replay.Action) error {
-	// TODO: record action
 	s.stateLock.Lock()
 	defer s.stateLock.Unlock()
 
-	playerIndex := s.clientToPlayer(clientID)
-	if playerIndex == skat.PlayerNone {
-		return ErrPlayerNotFound
+	playerIndex, err := s.clientToPlayer(clientID)
+	if err != nil {
+		return err
 	}
+	if playerIndex == skat.PlayerNone && !action.Kind().AllowedForDealer() {
+		return skat.ErrNotYourTurn
+	}
+	// keys are only forfeited by the server when the deadline passes;
+	// otherwise a player could forfeit their own key instead of losing
+	if action.Kind() == replay.ActionKindForfeitKey {
+		return ErrServerOnlyAction
+	}
+	return s.applyAction(playerIndex, action)
+}
 
+// Apply an action to the current game, record it and move on to the next
+// game if it finishes the current one
+//
+// Must be called with the state lock held.
+func (s *GameServer) applyAction(playerIndex int, action replay.Action) error {
+	prevPhase := s.currentGame.Phase()
 	err := action.Apply(s.currentGame, playerIndex)
 	s.l.Debugw("applied action",
 		"player", playerIndex,
 		"action", action.Kind(),
 	)
-	if err == nil {
-		s.pushState()
+	if err != nil {
+		return err
+	}
+	s.recordAction(playerIndex, action)
+
+	if !isFinished(prevPhase) && isFinished(s.currentGame.Phase()) {
+		s.finishGameLog()
+		if err := s.nextGame(); err != nil {
+			s.l.Errorw("failed to start the next game",
+				"err", err,
+			)
+		}
+	}
+	s.startAuditDeadline()
+	s.checkpoint()
+	s.pushState()
+	return nil
+}
+
+// Start the deadline for revealing the keys once the current game is audited
+//
+// Must be called with the state lock held.
+func (s *GameServer) startAuditDeadline() {
+	game := s.currentGame
+	if game.Phase() != skat.PhaseAudit || s.auditGame == game {
+		return
+	}
+	s.auditGame = game
+	time.AfterFunc(s.keyRevealTimeout, func() {
+		s.forfeitWithheldKey(game)
+	})
+}
+
+// Forfeit the key of the first player who has not revealed it in time
+func (s *GameServer) forfeitWithheldKey(game *skat.GameState) {
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+
+	if s.currentGame != game || game.Phase() != skat.PhaseAudit {
+		return
+	}
+	for player, key := range game.SealedDeal().SecretKeys {
+		if key != nil {
+			continue
+		}
+		s.l.Infow("key not revealed in time, forfeiting it",
+			"player", player,
+		)
+		if err := s.applyAction(player, &replay.ActionForfeitKey{}); err != nil {
+			s.l.Errorw("failed to forfeit key",
+				"player", player,
+				"err", err,
+			)
+		}
+		return
+	}
+}
+
+// Write a checkpoint, logging failures
+//
+// A failed checkpoint does not affect the running game.
+func (s *GameServer) checkpoint() {
+	if err := s.writeCheckpoint(); err != nil {
+		s.l.Errorw("failed to write checkpoint",
+			"stateFile", s.stateFile,
+			"err", err,
+		)
+	}
+}
+
+func isFinished(phase skat.GamePhase) bool {
+	return phase == skat.PhaseScored || phase == skat.PhasePassedIn
+}
+
+func blindedGameState(g *skat.GameState, playerIndex int) *skat.BlindedGameState {
+	if playerIndex == skat.PlayerNone {
+		return g.BlindedForDealer()
 	}
-	return err
+	return g.BlindedForPlayer(playerIndex)
 }
 
 func (s *GameServer) pushSingleState(ctx context.Context, clientID string) {
@@ -228,7 +498,10 @@ func (s *GameServer) pushSingleState(ctx context.Context, clientID string) {
 		)
 		return
 	}
-	playerIndex := clientInfo.playerIndex
+	playerIndex, err := s.clientToPlayer(clientID)
+	if err != nil {
+		return
+	}
 
 	ep := clientInfo.ep
 	if ep == nil {
@@ -239,8 +512,22 @@ func (s *GameServer) pushSingleState(ctx context.Context, clientID string) {
 		return
 	}
 
-	state := s.currentGame.BlindedForPlayer(playerIndex)
-	msg := NewStateMessage(playerIndex, state)
+	state := blindedGameState(s.currentGame, playerIndex)
+	var bock *skat.BockState
+	if s.bock.Config().Enabled {
+		bockState := s.bock.State()
+		bock = &bockState
+	}
+	msg := NewStateMessage(playerIndex, state, bock)
+	msg.YourSeat = clientInfo.playerIndex
+	msg.ScoreSheet = s.scoreSheet.Copy()
+	if s.lastGame != nil && s.currentGame.Phase() == skat.PhaseInit {
+		lastIndex := s.seatToPlayer(clientInfo.playerIndex, s.lastPlayerOffset)
+		msg.LastGame = &LastGameInfo{
+			YourPlayerIndex: lastIndex,
+			GameState:       blindedGameState(s.lastGame, lastIndex),
+		}
+	}
 	if err := ep.OneShot(ctx, msg); err != nil {
 		s.l.Warnw("failed to push state to client",
 			"clientID", clientID,
@@ -440,7 +727,7 @@ func (s *GameServer) ConnectClient(ep MessageEndpoint, ctx context.Context) erro
 		playerIndex = existing.playerIndex
 	} else {
 		playerIndex = len(s.playerReverseMap)
-		if len(s.playerReverseMap) >= 3 {
+		if len(s.playerReverseMap) >= s.seats {
 			s.l.Debugw("too many clients, rejecting new client",
 				"clientID", clientID,
 			)
@@ -479,6 +766,7 @@ func (s *GameServer) ConnectClient(ep MessageEndpoint, ctx context.Context) erro
 	}
 	if len(s.playerReverseMap) == playerIndex {
 		s.playerReverseMap = append(s.playerReverseMap, clientID)
+		s.checkpoint()
 	}
 
 	l.Infow("client connected successfully, refreshing worker")
@@ -0,0 +1,117 @@
+package singleuser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/horazont/webskat/internal/replay"
+	"github.com/horazont/webskat/internal/skat"
+)
+
+// Deal a sealed game and let the declarer resign, so that it waits for the
+// keys to be revealed
+func testGetAuditPhaseGame(t *testing.T, rules *skat.RuleSet) (*skat.GameState, [3]*skat.MentalPokerKey) {
+	g, err := skat.NewGame(false, skat.StandardScoreDefinition(), rules)
+	assert.Nil(t, err)
+	var keys [3]*skat.MentalPokerKey
+	for i := range keys {
+		keys[i], err = skat.GenerateMentalPokerKey()
+		assert.Nil(t, err)
+		assert.Nil(t, g.PublishKey(i, keys[i].Public()))
+	}
+	d := g.SealedDeal()
+	deck := skat.MentalPokerDeck()
+	for i, key := range keys {
+		deck, err = key.ShuffleDeck(deck)
+		assert.Nil(t, err)
+		assert.Nil(t, g.ShuffleDeck(i, deck))
+	}
+	for d.NextStripper() != skat.PlayerNone {
+		player := d.NextStripper()
+		strips, err := d.Strip(player, keys[player])
+		assert.Nil(t, err)
+		assert.Nil(t, g.StripCards(player, strips))
+	}
+
+	declarer := skat.PlayerInitialMiddlehand
+	assert.Nil(t, g.CallBid(declarer, 18))
+	assert.Nil(t, g.RespondToBid(skat.PlayerInitialForehand, false))
+	assert.Nil(t, g.CallBid(skat.PlayerInitialRearhand, skat.BidPass))
+	assert.Nil(t, g.Declare(declarer, skat.GameTypeGrand, skat.NoGameModifiers, nil))
+	assert.Nil(t, g.Resign(declarer))
+	assert.Equal(t, skat.PhaseAudit, g.Phase())
+	return g, keys
+}
+
+func TestGameServerKeyRevealTimeout(t *testing.T) {
+	t.Run("forfeits a key which is not revealed in time", func(t *testing.T) {
+		rules := skat.StandardRuleSet()
+		rules.Dealing = skat.DealingMentalPoker
+		s, err := NewGameServer(GameServerConfig{
+			Rules:            rules,
+			KeyRevealTimeout: 10 * time.Millisecond,
+		}, zap.NewNop().Sugar())
+		assert.Nil(t, err)
+
+		g, keys := testGetAuditPhaseGame(t, rules)
+		assert.Nil(t, g.RevealKey(skat.PlayerInitialForehand, keys[skat.PlayerInitialForehand].Bytes()))
+		assert.Nil(t, g.RevealKey(skat.PlayerInitialMiddlehand, keys[skat.PlayerInitialMiddlehand].Bytes()))
+		s.stateLock.Lock()
+		s.currentGame = g
+		s.startAuditDeadline()
+		s.stateLock.Unlock()
+
+		assert.Eventually(t, func() bool {
+			s.stateLock.Lock()
+			defer s.stateLock.Unlock()
+			return s.lastGame == g
+		}, time.Second, 5*time.Millisecond)
+		assert.Equal(t, skat.PhaseScored, g.Phase())
+		assert.Equal(t, skat.PlayerInitialRearhand, g.SealedDeal().Cheater)
+	})
+
+	t.Run("does not forfeit keys of a finished audit", func(t *testing.T) {
+		rules := skat.StandardRuleSet()
+		rules.Dealing = skat.DealingMentalPoker
+		s, err := NewGameServer(GameServerConfig{
+			Rules:            rules,
+			KeyRevealTimeout: 10 * time.Millisecond,
+		}, zap.NewNop().Sugar())
+		assert.Nil(t, err)
+
+		g, keys := testGetAuditPhaseGame(t, rules)
+		s.stateLock.Lock()
+		s.currentGame = g
+		s.startAuditDeadline()
+		for i, key := range keys {
+			assert.Nil(t, s.applyAction(i, replay.RevealKey(key)))
+		}
+		s.stateLock.Unlock()
+
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, skat.PhaseScored, g.Phase())
+		assert.Equal(t, skat.PlayerNone, g.SealedDeal().Cheater)
+	})
+
+	t.Run("does not let clients forfeit their own key", func(t *testing.T) {
+		rules := skat.StandardRuleSet()
+		rules.Dealing = skat.DealingMentalPoker
+		s, err := NewGameServer(GameServerConfig{
+			Rules: rules,
+		}, zap.NewNop().Sugar())
+		assert.Nil(t, err)
+
+		g, _ := testGetAuditPhaseGame(t, rules)
+		s.stateLock.Lock()
+		s.currentGame = g
+		s.clients["client"] = &gameClientConn{playerIndex: 0}
+		s.stateLock.Unlock()
+
+		assert.Equal(t, ErrServerOnlyAction, s.processAction("client", &replay.ActionForfeitKey{}))
+		assert.Equal(t, skat.PhaseAudit, g.Phase())
+		assert.Equal(t, skat.PlayerNone, g.SealedDeal().Cheater)
+	})
+}
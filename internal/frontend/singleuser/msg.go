@@ -123,15 +123,28 @@ func (m *AckMessage) Type() MessageType {
 	return MsgAck
 }
 
+// The outcome of the previous game of the session, as seen by the recipient
+type LastGameInfo struct {
+	YourPlayerIndex int                    `json:"playerIndex"`
+	GameState       *skat.BlindedGameState `json:"gameState"`
+}
+
 type StateMessage struct {
 	YourPlayerIndex int                    `json:"playerIndex"`
 	GameState       *skat.BlindedGameState `json:"gameState"`
+	Bock            *skat.BockState        `json:"bock,omitempty"`
+	// Seat of the recipient in the session, as used in the score sheet
+	YourSeat   int              `json:"seat"`
+	ScoreSheet *skat.ScoreSheet `json:"scoreSheet,omitempty"`
+	// Only set until the cards of the current game are dealt
+	LastGame *LastGameInfo `json:"lastGame,omitempty"`
 }
 
-func NewStateMessage(playerIndex int, gameState *skat.BlindedGameState) *StateMessage {
+func NewStateMessage(playerIndex int, gameState *skat.BlindedGameState, bock *skat.BockState) *StateMessage {
 	return &StateMessage{
 		YourPlayerIndex: playerIndex,
 		GameState:       gameState,
+		Bock:            bock,
 	}
 }
 
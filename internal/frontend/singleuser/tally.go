@@ -165,7 +165,7 @@ func (t *Tally) NewGame(playerIDs [3]string, dealerID string) (*skat.GameState,
 		return nil, errors.New("game in progress!")
 	}
 	var err error
-	t.currentGame, err = skat.NewGame(dealerID != "", skat.LeagueScoreDefinition())
+	t.currentGame, err = skat.NewGame(dealerID != "", skat.LeagueScoreDefinition(), skat.StandardRuleSet())
 	return t.currentGame, err
 }
 
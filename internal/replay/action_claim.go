@@ -0,0 +1,28 @@
+package replay
+
+import (
+	"encoding/json"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+type ActionClaim struct {
+	Tricks int `json:"tricks"`
+}
+
+func (a *ActionClaim) Apply(g *skat.GameState, player int) error {
+	return g.Claim(player, a.Tricks)
+}
+
+func (a *ActionClaim) Kind() ActionKind {
+	return ActionKindClaim
+}
+
+func DecodeActionClaim(msg []byte) (result *ActionClaim, err error) {
+	result = &ActionClaim{}
+	err = json.Unmarshal(msg, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,37 @@
+package replay
+
+import (
+	"encoding/json"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+type ActionCommitSeed struct {
+	Commitment []byte `json:"commitment"`
+}
+
+func (a *ActionCommitSeed) Apply(g *skat.GameState, player int) error {
+	if player == skat.PlayerNone {
+		// dealer
+		return g.CommitDealerSeed(a.Commitment)
+	} else {
+		return g.CommitSeed(player, a.Commitment)
+	}
+}
+
+func (a *ActionCommitSeed) Kind() ActionKind {
+	return ActionKindCommitSeed
+}
+
+func DecodeActionCommitSeed(msg []byte) (result *ActionCommitSeed, err error) {
+	result = &ActionCommitSeed{}
+	err = json.Unmarshal(msg, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func CommitSeed(seed []byte) *ActionCommitSeed {
+	return &ActionCommitSeed{Commitment: skat.CommitToSeed(seed)}
+}
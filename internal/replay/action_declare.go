@@ -10,9 +10,14 @@ type ActionDeclare struct {
 	GameType          skat.GameType
 	AnnounceModifiers skat.GameModifier
 	CardsToPush       skat.CardSet
+	// Only with a sealed deal, instead of CardsToPush
+	SlotsToPush []int `json:",omitempty"`
 }
 
 func (a *ActionDeclare) Apply(g *skat.GameState, player int) error {
+	if len(a.SlotsToPush) > 0 {
+		return g.DeclareSealed(player, a.GameType, a.AnnounceModifiers, a.SlotsToPush)
+	}
 	return g.Declare(player, a.GameType, a.AnnounceModifiers, a.CardsToPush)
 }
 
@@ -0,0 +1,29 @@
+package replay
+
+import (
+	"encoding/json"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+type ActionExchange struct {
+	Give skat.CardSet `json:"give"`
+	Take skat.CardSet `json:"take"`
+}
+
+func (a *ActionExchange) Apply(g *skat.GameState, player int) error {
+	return g.ExchangeCards(player, a.Give, a.Take)
+}
+
+func (a *ActionExchange) Kind() ActionKind {
+	return ActionKindExchange
+}
+
+func DecodeActionExchange(msg []byte) (result *ActionExchange, err error) {
+	result = &ActionExchange{}
+	err = json.Unmarshal(msg, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
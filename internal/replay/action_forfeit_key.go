@@ -0,0 +1,27 @@
+package replay
+
+import (
+	"encoding/json"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+type ActionForfeitKey struct {
+}
+
+func (a *ActionForfeitKey) Apply(g *skat.GameState, player int) error {
+	return g.ForfeitKey(player)
+}
+
+func (a *ActionForfeitKey) Kind() ActionKind {
+	return ActionKindForfeitKey
+}
+
+func DecodeActionForfeitKey(msg []byte) (result *ActionForfeitKey, err error) {
+	result = &ActionForfeitKey{}
+	err = json.Unmarshal(msg, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,27 @@
+package replay
+
+import (
+	"encoding/json"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+type ActionJunkPassOn struct {
+}
+
+func (a *ActionJunkPassOn) Apply(g *skat.GameState, player int) error {
+	return g.PassSkatOn(player)
+}
+
+func (a *ActionJunkPassOn) Kind() ActionKind {
+	return ActionKindJunkPassOn
+}
+
+func DecodeActionJunkPassOn(msg []byte) (result *ActionJunkPassOn, err error) {
+	result = &ActionJunkPassOn{}
+	err = json.Unmarshal(msg, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
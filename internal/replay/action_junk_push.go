@@ -0,0 +1,28 @@
+package replay
+
+import (
+	"encoding/json"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+type ActionJunkPush struct {
+	Cards skat.CardSet `json:"cards"`
+}
+
+func (a *ActionJunkPush) Apply(g *skat.GameState, player int) error {
+	return g.PushSkatOn(player, a.Cards)
+}
+
+func (a *ActionJunkPush) Kind() ActionKind {
+	return ActionKindJunkPush
+}
+
+func DecodeActionJunkPush(msg []byte) (result *ActionJunkPush, err error) {
+	result = &ActionJunkPush{}
+	err = json.Unmarshal(msg, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
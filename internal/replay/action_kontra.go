@@ -0,0 +1,27 @@
+package replay
+
+import (
+	"encoding/json"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+type ActionKontra struct {
+}
+
+func (a *ActionKontra) Apply(g *skat.GameState, player int) error {
+	return g.Kontra(player)
+}
+
+func (a *ActionKontra) Kind() ActionKind {
+	return ActionKindKontra
+}
+
+func DecodeActionKontra(msg []byte) (result *ActionKontra, err error) {
+	result = &ActionKontra{}
+	err = json.Unmarshal(msg, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
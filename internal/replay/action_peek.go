@@ -0,0 +1,28 @@
+package replay
+
+import (
+	"encoding/json"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+type ActionPeek struct {
+	Target skat.PeekTarget `json:"target"`
+}
+
+func (a *ActionPeek) Apply(g *skat.GameState, player int) error {
+	return g.Peek(player, a.Target)
+}
+
+func (a *ActionPeek) Kind() ActionKind {
+	return ActionKindPeek
+}
+
+func DecodeActionPeek(msg []byte) (result *ActionPeek, err error) {
+	result = &ActionPeek{}
+	err = json.Unmarshal(msg, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
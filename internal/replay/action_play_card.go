@@ -8,9 +8,14 @@ import (
 
 type ActionPlayCard struct {
 	Card skat.Card `json:"card"`
+	// Only with a sealed deal
+	Reveal *skat.CardReveal `json:"reveal,omitempty"`
 }
 
 func (a *ActionPlayCard) Apply(g *skat.GameState, player int) error {
+	if a.Reveal != nil {
+		return g.PlaySealedCard(player, a.Card, a.Reveal)
+	}
 	return g.PlayCard(player, a.Card)
 }
 
@@ -0,0 +1,32 @@
+package replay
+
+import (
+	"encoding/json"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+type ActionPublishKey struct {
+	Key []byte `json:"key"`
+}
+
+func (a *ActionPublishKey) Apply(g *skat.GameState, player int) error {
+	return g.PublishKey(player, a.Key)
+}
+
+func (a *ActionPublishKey) Kind() ActionKind {
+	return ActionKindPublishKey
+}
+
+func DecodeActionPublishKey(msg []byte) (result *ActionPublishKey, err error) {
+	result = &ActionPublishKey{}
+	err = json.Unmarshal(msg, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func PublishKey(key *skat.MentalPokerKey) *ActionPublishKey {
+	return &ActionPublishKey{Key: key.Public()}
+}
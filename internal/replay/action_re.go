@@ -0,0 +1,27 @@
+package replay
+
+import (
+	"encoding/json"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+type ActionRe struct {
+}
+
+func (a *ActionRe) Apply(g *skat.GameState, player int) error {
+	return g.Re(player)
+}
+
+func (a *ActionRe) Kind() ActionKind {
+	return ActionKindRe
+}
+
+func DecodeActionRe(msg []byte) (result *ActionRe, err error) {
+	result = &ActionRe{}
+	err = json.Unmarshal(msg, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,28 @@
+package replay
+
+import (
+	"encoding/json"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+type ActionReplyToClaim struct {
+	Accept bool `json:"accept"`
+}
+
+func (a *ActionReplyToClaim) Apply(g *skat.GameState, player int) error {
+	return g.RespondToClaim(player, a.Accept)
+}
+
+func (a *ActionReplyToClaim) Kind() ActionKind {
+	return ActionKindReplyToClaim
+}
+
+func DecodeActionReplyToClaim(msg []byte) (result *ActionReplyToClaim, err error) {
+	result = &ActionReplyToClaim{}
+	err = json.Unmarshal(msg, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,27 @@
+package replay
+
+import (
+	"encoding/json"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+type ActionResign struct {
+}
+
+func (a *ActionResign) Apply(g *skat.GameState, player int) error {
+	return g.Resign(player)
+}
+
+func (a *ActionResign) Kind() ActionKind {
+	return ActionKindResign
+}
+
+func DecodeActionResign(msg []byte) (result *ActionResign, err error) {
+	result = &ActionResign{}
+	err = json.Unmarshal(msg, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
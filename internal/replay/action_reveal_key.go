@@ -0,0 +1,32 @@
+package replay
+
+import (
+	"encoding/json"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+type ActionRevealKey struct {
+	Key []byte `json:"key"`
+}
+
+func (a *ActionRevealKey) Apply(g *skat.GameState, player int) error {
+	return g.RevealKey(player, a.Key)
+}
+
+func (a *ActionRevealKey) Kind() ActionKind {
+	return ActionKindRevealKey
+}
+
+func DecodeActionRevealKey(msg []byte) (result *ActionRevealKey, err error) {
+	result = &ActionRevealKey{}
+	err = json.Unmarshal(msg, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func RevealKey(key *skat.MentalPokerKey) *ActionRevealKey {
+	return &ActionRevealKey{Key: key.Bytes()}
+}
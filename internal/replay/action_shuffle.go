@@ -0,0 +1,28 @@
+package replay
+
+import (
+	"encoding/json"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+type ActionShuffle struct {
+	Deck [][]byte `json:"deck"`
+}
+
+func (a *ActionShuffle) Apply(g *skat.GameState, player int) error {
+	return g.ShuffleDeck(player, a.Deck)
+}
+
+func (a *ActionShuffle) Kind() ActionKind {
+	return ActionKindShuffle
+}
+
+func DecodeActionShuffle(msg []byte) (result *ActionShuffle, err error) {
+	result = &ActionShuffle{}
+	err = json.Unmarshal(msg, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
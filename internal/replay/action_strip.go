@@ -0,0 +1,28 @@
+package replay
+
+import (
+	"encoding/json"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+type ActionStrip struct {
+	Strips []skat.CardStrip `json:"strips"`
+}
+
+func (a *ActionStrip) Apply(g *skat.GameState, player int) error {
+	return g.StripCards(player, a.Strips)
+}
+
+func (a *ActionStrip) Kind() ActionKind {
+	return ActionKindStrip
+}
+
+func DecodeActionStrip(msg []byte) (result *ActionStrip, err error) {
+	result = &ActionStrip{}
+	err = json.Unmarshal(msg, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,32 @@
+package replay
+
+import (
+	"encoding/json"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+type ActionWatchHand struct {
+	Player int `json:"player"`
+}
+
+func (a *ActionWatchHand) Apply(g *skat.GameState, player int) error {
+	if player != skat.PlayerNone {
+		// only the dealer may watch
+		return skat.ErrNotYourTurn
+	}
+	return g.WatchHand(a.Player)
+}
+
+func (a *ActionWatchHand) Kind() ActionKind {
+	return ActionKindWatchHand
+}
+
+func DecodeActionWatchHand(msg []byte) (result *ActionWatchHand, err error) {
+	result = &ActionWatchHand{}
+	err = json.Unmarshal(msg, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,212 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+const (
+	// Version of the log format written by LogWriter
+	LogVersion = 2
+)
+
+var (
+	ErrLogVersion = errors.New("unsupported log version")
+	ErrNoHeader   = errors.New("log has no header")
+	ErrTrailing   = errors.New("log continues after the result")
+)
+
+// Everything needed to recreate a game before the first action
+type LogHeader struct {
+	Version    int                  `json:"version"`
+	Rules      skat.RuleSet         `json:"rules"`
+	Scoring    skat.ScoreDefinition `json:"scoring"`
+	ServerSeed skat.Seed            `json:"serverSeed"`
+	WithDealer bool                 `json:"withDealer"`
+	Schedule   skat.ScheduledGame   `json:"schedule"`
+}
+
+func NewLogHeader(g *skat.GameState) *LogHeader {
+	return &LogHeader{
+		Version:    LogVersion,
+		Rules:      *g.Rules(),
+		Scoring:    *g.Scoring(),
+		ServerSeed: g.ServerSeed(),
+		WithDealer: g.WithDealer(),
+		Schedule:   g.Schedule(),
+	}
+}
+
+// Create a game in PhaseInit as described by the header
+func (h *LogHeader) NewGame() (*skat.GameState, error) {
+	if h.Version != LogVersion {
+		return nil, ErrLogVersion
+	}
+	g, err := skat.NewGame(h.WithDealer, &h.Scoring, &h.Rules)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.Schedule.ApplyTo(g); err != nil {
+		return nil, err
+	}
+	if err := g.ForceServerSeed(h.ServerSeed); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// A single action applied to the game
+type LogRecord struct {
+	// skat.PlayerNone for the dealer
+	Player    int
+	Timestamp time.Time
+	Action    Action
+}
+
+type logRecordJSON struct {
+	Player    int             `json:"player"`
+	Timestamp time.Time       `json:"timestamp"`
+	Action    json.RawMessage `json:"action"`
+}
+
+func (r *LogRecord) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := ActionToJSON(r.Action, json.NewEncoder(buf)); err != nil {
+		return nil, err
+	}
+	return json.Marshal(&logRecordJSON{
+		Player:    r.Player,
+		Timestamp: r.Timestamp,
+		Action:    buf.Bytes(),
+	})
+}
+
+func (r *LogRecord) UnmarshalJSON(data []byte) error {
+	tmp := &logRecordJSON{}
+	if err := json.Unmarshal(data, tmp); err != nil {
+		return err
+	}
+	action, err := ActionFromJSON(json.NewDecoder(bytes.NewReader(tmp.Action)))
+	if err != nil {
+		return err
+	}
+	if action == nil {
+		return ErrUnknownAction
+	}
+	r.Player = tmp.Player
+	r.Timestamp = tmp.Timestamp
+	r.Action = action
+	return nil
+}
+
+// The outcome of a finished game, written after its last action
+type LogResult struct {
+	Phase skat.GamePhase `json:"phase"`
+	// Hands and skat as dealt
+	Hands  [3]skat.CardSet `json:"hands"`
+	Skat   skat.CardSet    `json:"skat"`
+	Scores [3]int          `json:"scores"`
+}
+
+func NewLogResult(g *skat.GameState) *LogResult {
+	result := &LogResult{
+		Phase: g.Phase(),
+	}
+	result.Hands, result.Skat = g.Dealt()
+	for i := range result.Scores {
+		result.Scores[i] = g.GetScore(i)
+	}
+	return result
+}
+
+type logResultJSON struct {
+	Result *LogResult `json:"result"`
+}
+
+// A recorded game
+type Log struct {
+	Header  *LogHeader
+	Records []*LogRecord
+	// nil if the game has not been finished
+	Result *LogResult
+}
+
+// Write a log as one JSON document per line
+//
+// The header comes first; records are only ever appended.
+type LogWriter struct {
+	enc *json.Encoder
+}
+
+func NewLogWriter(w io.Writer) *LogWriter {
+	return &LogWriter{
+		enc: json.NewEncoder(w),
+	}
+}
+
+func (w *LogWriter) WriteHeader(header *LogHeader) error {
+	return w.enc.Encode(header)
+}
+
+func (w *LogWriter) Append(record *LogRecord) error {
+	return w.enc.Encode(record)
+}
+
+// Conclude the log with the outcome of the game
+func (w *LogWriter) Finish(result *LogResult) error {
+	return w.enc.Encode(&logResultJSON{Result: result})
+}
+
+// Read a complete log as written by LogWriter
+func ReadLog(r io.Reader) (*Log, error) {
+	dec := json.NewDecoder(r)
+	header := &LogHeader{}
+	if err := dec.Decode(header); err != nil {
+		if err == io.EOF {
+			return nil, ErrNoHeader
+		}
+		return nil, err
+	}
+	if header.Version != LogVersion {
+		return nil, ErrLogVersion
+	}
+
+	result := &Log{
+		Header:  header,
+		Records: make([]*LogRecord, 0),
+	}
+	for {
+		var line json.RawMessage
+		err := dec.Decode(&line)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if result.Result != nil {
+			return nil, ErrTrailing
+		}
+
+		tail := &logResultJSON{}
+		if err := json.Unmarshal(line, tail); err != nil {
+			return nil, err
+		}
+		if tail.Result != nil {
+			result.Result = tail.Result
+			continue
+		}
+
+		record := &LogRecord{}
+		if err := json.Unmarshal(line, record); err != nil {
+			return nil, err
+		}
+		result.Records = append(result.Records, record)
+	}
+	return result, nil
+}
@@ -0,0 +1,114 @@
+package replay
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+// Record a complete hand game won in the bidding by middlehand
+//
+// All seeds are empty; every player plays the first card they are allowed
+// to.
+func testRecordLog(t *testing.T) *Log {
+	g, err := skat.NewGame(false, skat.LeagueScoreDefinition(), skat.StandardRuleSet())
+	assert.Nil(t, err)
+	assert.Nil(t, g.ForceServerSeed([]byte{}))
+	log := &Log{
+		Header:  NewLogHeader(g),
+		Records: make([]*LogRecord, 0),
+	}
+	timestamp := time.Date(2021, 3, 1, 20, 0, 0, 0, time.UTC)
+	record := func(player int, action Action) error {
+		if err := action.Apply(g, player); err != nil {
+			return err
+		}
+		log.Records = append(log.Records, &LogRecord{
+			Player:    player,
+			Timestamp: timestamp.Add(time.Duration(len(log.Records)) * time.Second),
+			Action:    action,
+		})
+		return nil
+	}
+
+	for i := 0; i < 3; i = i + 1 {
+		assert.Nil(t, record(i, CommitSeed([]byte{})))
+	}
+	for i := 0; i < 3; i = i + 1 {
+		assert.Nil(t, record(i, SetSeed([]byte{})))
+	}
+	assert.Nil(t, record(skat.PlayerInitialMiddlehand, &ActionCallBid{Value: 18}))
+	assert.Nil(t, record(skat.PlayerInitialForehand, &ActionReplyToBid{Hold: false}))
+	assert.Nil(t, record(skat.PlayerInitialRearhand, &ActionCallBid{Value: skat.BidPass}))
+	assert.Nil(t, record(skat.PlayerInitialMiddlehand, &ActionDeclare{GameType: skat.GameTypeGrand}))
+	for g.Phase() == skat.PhasePlaying {
+		player := g.Playing().GetCurrentPlayer()
+		played := false
+		for _, card := range g.GetHand(player) {
+			if record(player, &ActionPlayCard{Card: card}) == nil {
+				played = true
+				break
+			}
+		}
+		assert.True(t, played)
+	}
+	assert.Equal(t, skat.PhaseScored, g.Phase())
+	log.Result = NewLogResult(g)
+	return log
+}
+
+func testWriteLog(t *testing.T, log *Log) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	w := NewLogWriter(buf)
+	assert.Nil(t, w.WriteHeader(log.Header))
+	for _, record := range log.Records {
+		assert.Nil(t, w.Append(record))
+	}
+	if log.Result != nil {
+		assert.Nil(t, w.Finish(log.Result))
+	}
+	return buf
+}
+
+func TestLog(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		log := testRecordLog(t)
+		read, err := ReadLog(testWriteLog(t, log))
+		assert.Nil(t, err)
+		assert.Equal(t, log, read)
+	})
+
+	t.Run("unfinished log has no result", func(t *testing.T) {
+		log := testRecordLog(t)
+		log.Records = log.Records[:6]
+		log.Result = nil
+		read, err := ReadLog(testWriteLog(t, log))
+		assert.Nil(t, err)
+		assert.Equal(t, 6, len(read.Records))
+		assert.Nil(t, read.Result)
+	})
+
+	t.Run("rejects an empty log", func(t *testing.T) {
+		_, err := ReadLog(&bytes.Buffer{})
+		assert.Equal(t, ErrNoHeader, err)
+	})
+
+	t.Run("rejects other versions", func(t *testing.T) {
+		log := testRecordLog(t)
+		log.Header.Version = LogVersion + 1
+		_, err := ReadLog(testWriteLog(t, log))
+		assert.Equal(t, ErrLogVersion, err)
+	})
+
+	t.Run("rejects records after the result", func(t *testing.T) {
+		log := testRecordLog(t)
+		buf := testWriteLog(t, log)
+		assert.Nil(t, NewLogWriter(buf).Append(log.Records[0]))
+		_, err := ReadLog(buf)
+		assert.Equal(t, ErrTrailing, err)
+	})
+}
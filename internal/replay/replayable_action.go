@@ -10,8 +10,16 @@ import (
 type ActionKind string
 
 const (
-	// Init phase
-	ActionKindSetSeed ActionKind = "set_seed"
+	// Init phase; all parties commit to their seeds before revealing them
+	ActionKindCommitSeed ActionKind = "commit_seed"
+	ActionKindSetSeed    ActionKind = "set_seed"
+
+	// Init phase with a sealed deal; the players publish their keys, shuffle
+	// in turn and strip their encryption from the hands of the others
+	ActionKindPublishKey ActionKind = "publish_key"
+	ActionKindShuffle    ActionKind = "shuffle"
+	// Also valid in the declaration phase, for the skat
+	ActionKindStrip ActionKind = "strip"
 
 	// Bidding phase
 	ActionKindCallBid    ActionKind = "bid_call"
@@ -21,14 +29,30 @@ const (
 	ActionKindTakeSkat ActionKind = "take_skat"
 	ActionKindDeclare  ActionKind = "declare"
 
+	// Junk pushing phase (take_skat is also valid here)
+	ActionKindJunkPush   ActionKind = "junk_push"
+	ActionKindJunkPassOn ActionKind = "junk_pass_on"
+
+	// Exchange phase
+	ActionKindExchange ActionKind = "exchange"
+
 	// Playing phase
 	ActionKindPlayCard ActionKind = "play"
+	ActionKindKontra   ActionKind = "kontra"
+	ActionKindRe       ActionKind = "re"
+	ActionKindPeek     ActionKind = "peek"
+	ActionKindClaim    ActionKind = "claim"
+
+	ActionKindReplyToClaim ActionKind = "claim_reply"
 
 	// Declaration / Playing phases
-	ActionKindResign ActionKind = "resign"
+	ActionKindResign    ActionKind = "resign"
+	ActionKindWatchHand ActionKind = "watch_hand"
 
-	// Bidding / Declaration / Playing phases
-	ActionKindPeek ActionKind = "peek"
+	// Audit phase of a sealed deal; the server forfeits the keys which are
+	// not revealed in time
+	ActionKindRevealKey  ActionKind = "reveal_key"
+	ActionKindForfeitKey ActionKind = "forfeit_key"
 )
 
 const (
@@ -41,6 +65,14 @@ var (
 	ErrUnknownAction = errors.New("unknown action")
 )
 
+// Return true if the dealer who sits out a game may perform the action
+//
+// All other actions are applied with a player index and must not be applied
+// on behalf of the dealer.
+func (k ActionKind) AllowedForDealer() bool {
+	return k == ActionKindCommitSeed || k == ActionKindSetSeed || k == ActionKindWatchHand
+}
+
 type Action interface {
 	Apply(g *skat.GameState, player int) error
 	Kind() ActionKind
@@ -59,8 +91,16 @@ func ActionFromJSON(dec *json.Decoder) (Action, error) {
 	}
 
 	switch ActionKind(ia.Kind) {
+	case ActionKindCommitSeed:
+		return DecodeActionCommitSeed(ia.ActionPayload)
 	case ActionKindSetSeed:
 		return DecodeActionSetSeed(ia.ActionPayload)
+	case ActionKindPublishKey:
+		return DecodeActionPublishKey(ia.ActionPayload)
+	case ActionKindShuffle:
+		return DecodeActionShuffle(ia.ActionPayload)
+	case ActionKindStrip:
+		return DecodeActionStrip(ia.ActionPayload)
 	case ActionKindCallBid:
 		return DecodeActionCallBid(ia.ActionPayload)
 	case ActionKindReplyToBid:
@@ -69,8 +109,32 @@ func ActionFromJSON(dec *json.Decoder) (Action, error) {
 		return DecodeActionTakeSkat(ia.ActionPayload)
 	case ActionKindDeclare:
 		return DecodeActionDeclare(ia.ActionPayload)
+	case ActionKindJunkPush:
+		return DecodeActionJunkPush(ia.ActionPayload)
+	case ActionKindJunkPassOn:
+		return DecodeActionJunkPassOn(ia.ActionPayload)
+	case ActionKindExchange:
+		return DecodeActionExchange(ia.ActionPayload)
 	case ActionKindPlayCard:
 		return DecodeActionPlayCard(ia.ActionPayload)
+	case ActionKindKontra:
+		return DecodeActionKontra(ia.ActionPayload)
+	case ActionKindRe:
+		return DecodeActionRe(ia.ActionPayload)
+	case ActionKindResign:
+		return DecodeActionResign(ia.ActionPayload)
+	case ActionKindPeek:
+		return DecodeActionPeek(ia.ActionPayload)
+	case ActionKindClaim:
+		return DecodeActionClaim(ia.ActionPayload)
+	case ActionKindReplyToClaim:
+		return DecodeActionReplyToClaim(ia.ActionPayload)
+	case ActionKindWatchHand:
+		return DecodeActionWatchHand(ia.ActionPayload)
+	case ActionKindRevealKey:
+		return DecodeActionRevealKey(ia.ActionPayload)
+	case ActionKindForfeitKey:
+		return DecodeActionForfeitKey(ia.ActionPayload)
 	}
 
 	return nil, nil
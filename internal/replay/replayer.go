@@ -0,0 +1,104 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+var (
+	ErrReplayDone = errors.New("all actions have been replayed")
+)
+
+// An action of a log which could not be applied
+type ReplayError struct {
+	// Index of the record in the log
+	Step   int
+	Record *LogRecord
+	Err    error
+}
+
+func (e *ReplayError) Error() string {
+	return fmt.Sprintf("step %d (%s by player %d): %s", e.Step, e.Record.Action.Kind(), e.Record.Player, e.Err)
+}
+
+func (e *ReplayError) Unwrap() error {
+	return e.Err
+}
+
+// Rebuild a game from a log, one action at a time
+type Replayer struct {
+	log  *Log
+	game *skat.GameState
+	step int
+}
+
+func NewReplayer(log *Log) (*Replayer, error) {
+	game, err := log.Header.NewGame()
+	if err != nil {
+		return nil, err
+	}
+	return &Replayer{
+		log:  log,
+		game: game,
+	}, nil
+}
+
+func (r *Replayer) Game() *skat.GameState {
+	return r.game
+}
+
+// Return the number of actions applied so far
+func (r *Replayer) Position() int {
+	return r.step
+}
+
+func (r *Replayer) Done() bool {
+	return r.step >= len(r.log.Records)
+}
+
+// Apply the next action
+//
+// Returns a *ReplayError if the action fails; the replayer does not advance in
+// that case. Returns ErrReplayDone if the log has ended.
+func (r *Replayer) Step() error {
+	if r.Done() {
+		return ErrReplayDone
+	}
+	record := r.log.Records[r.step]
+	if record.Player == skat.PlayerNone && !record.Action.Kind().AllowedForDealer() {
+		return &ReplayError{Step: r.step, Record: record, Err: skat.ErrNotYourTurn}
+	}
+	if err := record.Action.Apply(r.game, record.Player); err != nil {
+		return &ReplayError{Step: r.step, Record: record, Err: err}
+	}
+	r.step = r.step + 1
+	return nil
+}
+
+// Apply actions until the given number of actions has been applied in total
+// or the log ends
+//
+// A negative number replays the whole log.
+func (r *Replayer) Run(stopAfter int) error {
+	for !r.Done() && (stopAfter < 0 || r.step < stopAfter) {
+		if err := r.Step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rebuild the game of a log, stopping after the given number of actions
+//
+// A negative number replays the whole log. On failure, the game is returned
+// in the state before the failing action together with a *ReplayError.
+func Replay(log *Log, stopAfter int) (*skat.GameState, error) {
+	r, err := NewReplayer(log)
+	if err != nil {
+		return nil, err
+	}
+	err = r.Run(stopAfter)
+	return r.Game(), err
+}
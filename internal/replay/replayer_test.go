@@ -0,0 +1,64 @@
+package replay
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/horazont/webskat/internal/skat"
+)
+
+func TestReplayer(t *testing.T) {
+	t.Run("replays the whole log", func(t *testing.T) {
+		log := testRecordLog(t)
+		g, err := Replay(log, -1)
+		assert.Nil(t, err)
+		assert.Equal(t, log.Result, NewLogResult(g))
+	})
+
+	t.Run("stops after the given number of steps", func(t *testing.T) {
+		log := testRecordLog(t)
+		r, err := NewReplayer(log)
+		assert.Nil(t, err)
+		assert.Nil(t, r.Run(6))
+		assert.Equal(t, 6, r.Position())
+		assert.False(t, r.Done())
+		assert.Equal(t, skat.PhaseBidding, r.Game().Phase())
+
+		assert.Nil(t, r.Step())
+		assert.Equal(t, 7, r.Position())
+	})
+
+	t.Run("reports the first failing step", func(t *testing.T) {
+		log := testRecordLog(t)
+		log.Records[7].Player = skat.PlayerInitialRearhand
+		log.Records[8].Player = skat.PlayerInitialForehand
+		g, err := Replay(log, -1)
+		replayErr := &ReplayError{}
+		assert.True(t, errors.As(err, &replayErr))
+		assert.Equal(t, 7, replayErr.Step)
+		assert.Equal(t, log.Records[7], replayErr.Record)
+		assert.Equal(t, skat.ErrNotYourTurn, errors.Unwrap(err))
+		assert.Equal(t, skat.PhaseBidding, g.Phase())
+	})
+
+	t.Run("does not advance on failure", func(t *testing.T) {
+		log := testRecordLog(t)
+		log.Records[6].Action = &ActionCallBid{Value: 17}
+		r, err := NewReplayer(log)
+		assert.Nil(t, err)
+		assert.NotNil(t, r.Run(-1))
+		assert.Equal(t, 6, r.Position())
+		assert.NotNil(t, r.Step())
+		assert.Equal(t, 6, r.Position())
+	})
+
+	t.Run("rejects stepping past the end", func(t *testing.T) {
+		r, err := NewReplayer(testRecordLog(t))
+		assert.Nil(t, err)
+		assert.Nil(t, r.Run(-1))
+		assert.True(t, r.Done())
+		assert.Equal(t, ErrReplayDone, r.Step())
+	})
+}
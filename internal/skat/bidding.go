@@ -2,6 +2,7 @@ package skat
 
 import (
 	"errors"
+	"sort"
 )
 
 const (
@@ -10,12 +11,59 @@ const (
 
 	// Player has passed at 18
 	BidPass = 0
+
+	// Lowest possible bid
+	MinimumBid = 18
+
+	// Highest multiplier of a suit game: with or without eleven, game, hand,
+	// schneider, schneider announced, schwarz, schwarz announced, ouvert
+	maxSuitFactor = 18
+	// Highest multiplier of a grand: with or without four, plus the same
+	// modifiers as above
+	maxGrandFactor = 11
 )
 
 var (
-	ErrBidTooLow = errors.New("bid value too low")
+	ErrBidTooLow  = errors.New("bid value too low")
+	ErrInvalidBid = errors.New("bid value is not a possible game value")
+
+	bidLadder = standardRules.BidLadder()
 )
 
+// Return all values which can be bid under the standard rules, in ascending
+// order
+func BidLadder() []int {
+	result := make([]int, len(bidLadder))
+	copy(result, bidLadder)
+	return result
+}
+
+// Test whether a value can be bid under the standard rules
+func IsValidBid(value int) bool {
+	return isOnLadder(bidLadder, value)
+}
+
+// Return the lowest value on the standard bid ladder which is higher than the
+// given value
+//
+// Returns BidNone if there is no higher value.
+func NextBid(value int) int {
+	return nextOnLadder(bidLadder, value)
+}
+
+func isOnLadder(ladder []int, value int) bool {
+	index := sort.SearchInts(ladder, value)
+	return index < len(ladder) && ladder[index] == value
+}
+
+func nextOnLadder(ladder []int, value int) int {
+	index := sort.SearchInts(ladder, value+1)
+	if index >= len(ladder) {
+		return BidNone
+	}
+	return ladder[index]
+}
+
 type BiddingPlayerState struct {
 	LastBid      int
 	HasPassedBid bool
@@ -33,9 +81,14 @@ type BiddingState struct {
 	declarer         int
 	awaitingResponse bool
 	lastBid          int
+	ladder           []int
 }
 
 func NewBiddingState() *BiddingState {
+	return NewBiddingStateForRules(standardRules)
+}
+
+func NewBiddingStateForRules(rules *RuleSet) *BiddingState {
 	return &BiddingState{
 		players: [3]BiddingPlayerState{
 			NewBiddingPlayerState(),
@@ -45,6 +98,7 @@ func NewBiddingState() *BiddingState {
 		declarer:         PlayerNone,
 		awaitingResponse: false,
 		lastBid:          BidNone,
+		ladder:           rules.BidLadder(),
 	}
 }
 
@@ -148,6 +202,21 @@ func (b *BiddingState) LastBid() int {
 	return b.lastBid
 }
 
+// Return the lowest value the current caller may bid
+//
+// Returns BidNone if the caller cannot outbid the last bid.
+func (b *BiddingState) NextLegalBid() int {
+	return nextOnLadder(b.ladder, b.lastBid)
+}
+
+// Return all values the current caller may bid, in ascending order
+func (b *BiddingState) LegalBids() []int {
+	index := sort.SearchInts(b.ladder, b.lastBid+1)
+	result := make([]int, len(b.ladder)-index)
+	copy(result, b.ladder[index:])
+	return result
+}
+
 // Returns the declarer
 //
 // If Done() is false or if all players have passed without placing any bid,
@@ -168,9 +237,12 @@ func (b *BiddingState) Call(player int, value int) error {
 	if value == BidPass {
 		b.players[player].HasPassedBid = true
 	} else {
-		if b.players[player].LastBid >= value {
+		if b.players[player].LastBid >= value || b.lastBid >= value {
 			return ErrBidTooLow
 		}
+		if !isOnLadder(b.ladder, value) {
+			return ErrInvalidBid
+		}
 		b.players[player].LastBid = value
 		b.lastBid = value
 		b.awaitingResponse = true
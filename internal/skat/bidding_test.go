@@ -96,6 +96,41 @@ func TestBiddingOrder(t *testing.T) {
 		assert.Equal(t, PlayerInitialMiddlehand, b.Caller())
 	})
 
+	t.Run("reject bid off the ladder", func(t *testing.T) {
+		b := NewBiddingState()
+		var err error
+
+		err = b.Call(PlayerInitialMiddlehand, 19)
+		assert.Equal(t, ErrInvalidBid, err)
+		assert.False(t, b.AwaitingResponse())
+
+		err = b.Call(PlayerInitialMiddlehand, 1000)
+		assert.Equal(t, ErrInvalidBid, err)
+		assert.False(t, b.AwaitingResponse())
+
+		err = b.Call(PlayerInitialMiddlehand, 23)
+		assert.Nil(t, err)
+		assert.True(t, b.AwaitingResponse())
+	})
+
+	t.Run("reject bid below the last bid of another player", func(t *testing.T) {
+		b := NewBiddingState()
+		var err error
+
+		err = b.Call(PlayerInitialMiddlehand, 20)
+		assert.Nil(t, err)
+
+		err = b.Respond(PlayerInitialForehand, true)
+		assert.Nil(t, err)
+
+		err = b.Call(PlayerInitialMiddlehand, BidPass)
+		assert.Nil(t, err)
+
+		err = b.Call(PlayerInitialRearhand, 18)
+		assert.Equal(t, ErrBidTooLow, err)
+		assert.Equal(t, 22, b.NextLegalBid())
+	})
+
 	t.Run("18-pass-pass: middlehand takes with 18", func(t *testing.T) {
 		b := NewBiddingState()
 		var err error
@@ -383,3 +418,44 @@ func TestBiddingOrder(t *testing.T) {
 		assert.Equal(t, 0, b.CalledGameValue())
 	})
 }
+
+func TestBidLadder(t *testing.T) {
+	t.Run("starts with the usual values", func(t *testing.T) {
+		ladder := BidLadder()
+		assert.Equal(t, []int{18, 20, 22, 23, 24, 27, 30, 33, 35, 36, 40, 44, 45, 46, 48, 50}, ladder[:16])
+	})
+
+	t.Run("ends with the highest possible game", func(t *testing.T) {
+		ladder := BidLadder()
+		assert.Equal(t, 264, ladder[len(ladder)-1])
+	})
+
+	t.Run("valid bids", func(t *testing.T) {
+		assert.True(t, IsValidBid(18))
+		assert.True(t, IsValidBid(59))
+		assert.True(t, IsValidBid(216))
+		assert.False(t, IsValidBid(BidPass))
+		assert.False(t, IsValidBid(9))
+		assert.False(t, IsValidBid(19))
+		assert.False(t, IsValidBid(1000))
+	})
+
+	t.Run("next bid", func(t *testing.T) {
+		assert.Equal(t, 18, NextBid(BidNone))
+		assert.Equal(t, 18, NextBid(BidPass))
+		assert.Equal(t, 20, NextBid(18))
+		assert.Equal(t, 22, NextBid(21))
+		assert.Equal(t, BidNone, NextBid(264))
+	})
+
+	t.Run("legal bids follow the last bid", func(t *testing.T) {
+		b := NewBiddingState()
+		assert.Equal(t, 18, b.NextLegalBid())
+		assert.Equal(t, BidLadder(), b.LegalBids())
+
+		assert.Nil(t, b.Call(PlayerInitialMiddlehand, 33))
+		assert.Equal(t, 35, b.NextLegalBid())
+		assert.Equal(t, 35, b.LegalBids()[0])
+		assert.Equal(t, 264, b.LegalBids()[len(b.LegalBids())-1])
+	})
+}
@@ -2,10 +2,13 @@ package skat
 
 type BlindedPlayerState struct {
 	Ncards        int  `json:"ncards"`
+	SeedCommitted bool `json:"seedCommitted"`
 	SeedProvided  bool `json:"seedProvided"`
 	WonCardPoints int  `json:"wonPoints"`
 	AwardedScore  int  `json:"awardedScore"`
 	Seed          Seed `json:"seed"`
+	Resigned      bool `json:"resigned"`
+	Exchanged     bool `json:"exchanged"`
 }
 
 type BlindedBiddingState struct {
@@ -13,16 +16,41 @@ type BlindedBiddingState struct {
 	Caller           int  `json:"caller"`
 	Responder        int  `json:"responder"`
 	AwaitingResponse bool `json:"awaitingResponse"`
+	NextBid          int  `json:"nextBid"`
+}
+
+// The full deal of a finished game
+//
+// The tricks are part of BlindedGameState.Tricks.
+type GameReveal struct {
+	// Hands as dealt, indexed by player
+	Hands []CardSet `json:"hands"`
+	// Skat as dealt
+	Skat CardSet `json:"skat"`
+	// Cards pushed by the declarer, or the cards pushed on last in
+	// Schieberamsch
+	Pushed CardSet `json:"pushed,omitempty"`
 }
 
 type BlindedGameState struct {
 	Phase GamePhase `json:"phase"`
 
 	// Common state
-	Players    []BlindedPlayerState `json:"players"`
-	Hand       CardSet              `json:"hand"`
-	SkatCards  int                  `json:"skatCards"`
-	ServerSeed Seed                 `json:"serverSeed"`
+	Players   []BlindedPlayerState `json:"players"`
+	Hand      CardSet              `json:"hand"`
+	SkatCards int                  `json:"skatCards"`
+	// Only sent once all parties have committed to their seeds
+	ServerSeed       Seed     `json:"serverSeed"`
+	ServerCommitment []byte   `json:"serverCommitment"`
+	SeedsCommitted   bool     `json:"seedsCommitted"`
+	Multiplier       int      `json:"multiplier"`
+	Rules            *RuleSet `json:"rules"`
+
+	WithDealer          bool `json:"withDealer"`
+	DealerSeedCommitted bool `json:"dealerSeedCommitted"`
+	DealerSeedProvided  bool `json:"dealerSeedProvided"`
+	// Player whose hand the dealer is watching
+	WatchedPlayer int `json:"watchedPlayer"`
 
 	// Bidding state
 	BiddingState    *BlindedBiddingState
@@ -34,12 +62,35 @@ type BlindedGameState struct {
 	CurrentPlayer      int          `json:"currentPlayer"`
 	GameType           GameType     `json:"gameType"`
 	AnnouncedModifiers GameModifier `json:"announcedModifiers"`
+	KontraPlayer       int          `json:"kontraPlayer"`
 	Table              CardSet      `json:"table"`
+	// Hands visible to everyone, indexed by player; nil for hidden hands
+	OpenHands []CardSet `json:"openHands,omitempty"`
+
+	Claim *Claim `json:"claim,omitempty"`
+	// Cards the player may play, only sent to the player on turn
+	LegalCards CardSet `json:"legalCards,omitempty"`
+
+	// Hand of the other defender while exchanging cards in Revolution
+	PartnerHand CardSet `json:"partnerHand,omitempty"`
+
+	// Decisions on the skat in Schieberamsch, in order
+	SkatPasses []SkatPass `json:"skatPasses,omitempty"`
+
+	// Public state of a sealed deal, without the shuffles; see Unseal
+	SealedDeal *SealedDeal `json:"sealedDeal,omitempty"`
+
+	// Only filled in after peeking
+	LastTrick       CardSet `json:"lastTrick,omitempty"`
+	LastTrickWinner int     `json:"lastTrickWinner"`
+	PushedCards     CardSet `json:"pushedCards,omitempty"`
 
 	// Scored state
-	LossReason     string       `json:"lossReason"`
-	FinalModifiers GameModifier `json:"finalModifiers"`
-	FinalGameValue int          `json:"finalGameValue"`
-	JackStrength   int          `json:"jackStrength"`
-	DealerSeed     Seed         `json:"dealerSeed"`
+	LossReason     string        `json:"lossReason"`
+	FinalModifiers GameModifier  `json:"finalModifiers"`
+	FinalGameValue int           `json:"finalGameValue"`
+	JackStrength   int           `json:"jackStrength"`
+	DealerSeed     Seed          `json:"dealerSeed"`
+	Reveal         *GameReveal   `json:"reveal,omitempty"`
+	Tricks         []TrickRecord `json:"tricks,omitempty"`
 }
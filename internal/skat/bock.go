@@ -0,0 +1,172 @@
+package skat
+
+import (
+	"errors"
+)
+
+var (
+	ErrGameNotScored = errors.New("game has not been scored yet")
+)
+
+type BockConfig struct {
+	Enabled bool `json:"enabled"`
+	// Number of games in a Bock round, usually one per player
+	RoundLength int `json:"roundLength"`
+	// Factor by which the score of each game in a Bock round is multiplied
+	Multiplier int `json:"multiplier"`
+
+	// Triggers for a Bock round
+	OnLostKontra bool `json:"onLostKontra"`
+	OnSixtySixty bool `json:"onSixtySixty"`
+	OnGrandHand  bool `json:"onGrandHand"`
+
+	// Follow each Bock round with a round of Junk games
+	JunkRounds bool `json:"junkRounds"`
+}
+
+func DefaultBockConfig() BockConfig {
+	return BockConfig{
+		Enabled:      false,
+		RoundLength:  3,
+		Multiplier:   2,
+		OnLostKontra: true,
+		OnSixtySixty: true,
+		OnGrandHand:  true,
+		JunkRounds:   false,
+	}
+}
+
+// Parameters for a single upcoming game
+type ScheduledGame struct {
+	Multiplier int  `json:"multiplier"`
+	Junk       bool `json:"junk"`
+}
+
+func NormalGame() ScheduledGame {
+	return ScheduledGame{
+		Multiplier: 1,
+		Junk:       false,
+	}
+}
+
+func (sg ScheduledGame) IsBock() bool {
+	return sg.Multiplier > 1
+}
+
+// Apply the schedule to a game which has not been dealt yet
+func (sg ScheduledGame) ApplyTo(g *GameState) error {
+	if err := g.SetMultiplier(sg.Multiplier); err != nil {
+		return err
+	}
+	if sg.Junk {
+		return g.ForceJunk()
+	}
+	return nil
+}
+
+type BockState struct {
+	Pending []ScheduledGame `json:"pending"`
+}
+
+// Keep track of Bock and Junk rounds over a sequence of games
+//
+// Each scored game is passed to Observe(), which queues a Bock round (and
+// optionally a Junk round) if the game triggers one. Next() returns the
+// parameters of the following game.
+type BockScheduler struct {
+	config  BockConfig
+	pending []ScheduledGame
+}
+
+func NewBockScheduler(config BockConfig) *BockScheduler {
+	return &BockScheduler{
+		config:  config,
+		pending: make([]ScheduledGame, 0),
+	}
+}
+
+func (s *BockScheduler) Config() BockConfig {
+	return s.config
+}
+
+// Test whether the scored game triggers a Bock round
+func (s *BockScheduler) Triggers(g *GameState) bool {
+	if !s.config.Enabled {
+		return false
+	}
+	declarer := g.Declarer()
+	if declarer == PlayerNone {
+		return false
+	}
+	modifiers := g.Modifiers()
+	if s.config.OnLostKontra && modifiers.Test(GameModifierKontra) && g.GetLossReason() == "" {
+		return true
+	}
+	if s.config.OnSixtySixty && g.players[declarer].WonCards.Value() == 60 {
+		return true
+	}
+	if s.config.OnGrandHand && g.GameType() == GameTypeGrand && modifiers.Test(GameModifierHand) {
+		return true
+	}
+	return false
+}
+
+// Feed a scored game to the scheduler
+func (s *BockScheduler) Observe(g *GameState) error {
+	if g.Phase() != PhaseScored {
+		return ErrGameNotScored
+	}
+	if !s.Triggers(g) {
+		return nil
+	}
+	for i := 0; i < s.config.RoundLength; i = i + 1 {
+		s.pending = append(s.pending, ScheduledGame{
+			Multiplier: s.config.Multiplier,
+			Junk:       false,
+		})
+	}
+	if s.config.JunkRounds {
+		for i := 0; i < s.config.RoundLength; i = i + 1 {
+			s.pending = append(s.pending, ScheduledGame{
+				Multiplier: 1,
+				Junk:       true,
+			})
+		}
+	}
+	return nil
+}
+
+// Return the parameters of the next game without consuming them
+func (s *BockScheduler) Peek() ScheduledGame {
+	if len(s.pending) == 0 {
+		return NormalGame()
+	}
+	return s.pending[0]
+}
+
+// Consume and return the parameters of the next game
+func (s *BockScheduler) Next() ScheduledGame {
+	result := s.Peek()
+	if len(s.pending) > 0 {
+		s.pending = s.pending[1:]
+	}
+	return result
+}
+
+func (s *BockScheduler) State() BockState {
+	pending := make([]ScheduledGame, len(s.pending))
+	copy(pending, s.pending)
+	return BockState{
+		Pending: pending,
+	}
+}
+
+// Recreate a scheduler with the pending games of a previous State()
+func RestoreBockScheduler(config BockConfig, state BockState) *BockScheduler {
+	pending := make([]ScheduledGame, len(state.Pending))
+	copy(pending, state.Pending)
+	return &BockScheduler{
+		config:  config,
+		pending: pending,
+	}
+}
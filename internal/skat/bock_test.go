@@ -0,0 +1,150 @@
+package skat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testBockConfig() BockConfig {
+	config := DefaultBockConfig()
+	config.Enabled = true
+	return config
+}
+
+func testGetScoredGame(t *testing.T, gameType GameType, kontra bool) *GameState {
+	g := testGetPlayingPhaseGame(t, gameType)
+	if kontra {
+		assert.Nil(t, g.Kontra(PlayerInitialForehand))
+	}
+	testPlayOut(t, g.Playing(), g.Playing().Play, -1)
+	assert.Nil(t, g.EvaluateGame())
+	return g
+}
+
+func TestBockScheduler(t *testing.T) {
+	t.Run("returns normal games without triggers", func(t *testing.T) {
+		s := NewBockScheduler(testBockConfig())
+		assert.Equal(t, NormalGame(), s.Next())
+		assert.Equal(t, 0, len(s.State().Pending))
+	})
+
+	t.Run("rejects games which have not been scored", func(t *testing.T) {
+		s := NewBockScheduler(testBockConfig())
+		g := testGetPlayingPhaseGame(t, GameTypeSpades)
+		assert.Equal(t, ErrGameNotScored, s.Observe(g))
+	})
+
+	t.Run("lost game without kontra does not trigger", func(t *testing.T) {
+		s := NewBockScheduler(testBockConfig())
+		assert.Nil(t, s.Observe(testGetScoredGame(t, GameTypeDiamonds, false)))
+		assert.Equal(t, 0, len(s.State().Pending))
+	})
+
+	t.Run("kontra won by the defenders does not trigger", func(t *testing.T) {
+		s := NewBockScheduler(testBockConfig())
+		assert.Nil(t, s.Observe(testGetScoredGame(t, GameTypeDiamonds, true)))
+		assert.Equal(t, 0, len(s.State().Pending))
+	})
+
+	t.Run("lost kontra triggers a bock round", func(t *testing.T) {
+		s := NewBockScheduler(testBockConfig())
+		assert.Nil(t, s.Observe(testGetScoredGame(t, GameTypeSpades, true)))
+		assert.Equal(t, 3, len(s.State().Pending))
+		for i := 0; i < 3; i = i + 1 {
+			next := s.Next()
+			assert.True(t, next.IsBock())
+			assert.Equal(t, 2, next.Multiplier)
+			assert.False(t, next.Junk)
+		}
+		assert.Equal(t, NormalGame(), s.Next())
+	})
+
+	t.Run("grand hand triggers a bock round", func(t *testing.T) {
+		s := NewBockScheduler(testBockConfig())
+		assert.Nil(t, s.Observe(testGetScoredGame(t, GameTypeGrand, false)))
+		assert.Equal(t, 3, len(s.State().Pending))
+	})
+
+	t.Run("triggers can be disabled", func(t *testing.T) {
+		config := testBockConfig()
+		config.OnGrandHand = false
+		s := NewBockScheduler(config)
+		assert.Nil(t, s.Observe(testGetScoredGame(t, GameTypeGrand, false)))
+		assert.Equal(t, 0, len(s.State().Pending))
+
+		s = NewBockScheduler(DefaultBockConfig())
+		assert.Nil(t, s.Observe(testGetScoredGame(t, GameTypeGrand, false)))
+		assert.Equal(t, 0, len(s.State().Pending))
+	})
+
+	t.Run("bock rounds stack", func(t *testing.T) {
+		s := NewBockScheduler(testBockConfig())
+		assert.Nil(t, s.Observe(testGetScoredGame(t, GameTypeGrand, false)))
+		assert.Nil(t, s.Observe(testGetScoredGame(t, GameTypeGrand, false)))
+		assert.Equal(t, 6, len(s.State().Pending))
+	})
+
+	t.Run("junk round follows bock round", func(t *testing.T) {
+		config := testBockConfig()
+		config.JunkRounds = true
+		config.RoundLength = 1
+		s := NewBockScheduler(config)
+		assert.Nil(t, s.Observe(testGetScoredGame(t, GameTypeGrand, false)))
+		assert.Equal(t, ScheduledGame{Multiplier: 2, Junk: false}, s.Next())
+		assert.Equal(t, ScheduledGame{Multiplier: 1, Junk: true}, s.Next())
+		assert.Equal(t, NormalGame(), s.Next())
+	})
+
+	t.Run("restore keeps pending games", func(t *testing.T) {
+		s := NewBockScheduler(testBockConfig())
+		assert.Nil(t, s.Observe(testGetScoredGame(t, GameTypeGrand, false)))
+		s.Next()
+		restored := RestoreBockScheduler(s.Config(), s.State())
+		assert.Equal(t, s.State(), restored.State())
+		assert.Equal(t, s.Next(), restored.Next())
+	})
+
+	t.Run("peek does not consume", func(t *testing.T) {
+		s := NewBockScheduler(testBockConfig())
+		assert.Nil(t, s.Observe(testGetScoredGame(t, GameTypeGrand, false)))
+		assert.True(t, s.Peek().IsBock())
+		assert.Equal(t, 3, len(s.State().Pending))
+	})
+}
+
+func TestScheduledGameApplyTo(t *testing.T) {
+	t.Run("bock game doubles the score", func(t *testing.T) {
+		g := testGetInitPhaseGame(t)
+		assert.Nil(t, ScheduledGame{Multiplier: 2}.ApplyTo(g))
+		testDeal(t, g)
+		testWinBidding(t, g)
+		assert.Nil(t, g.Declare(PlayerInitialMiddlehand, GameTypeDiamonds, NoGameModifiers, nil))
+		testPlayOut(t, g.Playing(), g.Playing().Play, -1)
+		assert.Nil(t, g.EvaluateGame())
+		assert.Equal(t, -108, g.GetScore(PlayerInitialMiddlehand))
+		// the league bonus for the defenders is not affected
+		assert.Equal(t, 40, g.GetScore(PlayerInitialForehand))
+		assert.Equal(t, 2, g.BlindedForPlayer(PlayerInitialForehand).Multiplier)
+	})
+
+	t.Run("junk game skips bidding", func(t *testing.T) {
+		g := testGetInitPhaseGame(t)
+		assert.Nil(t, ScheduledGame{Multiplier: 1, Junk: true}.ApplyTo(g))
+		testDeal(t, g)
+		assert.Equal(t, PhasePlaying, g.Phase())
+		assert.Equal(t, GameTypeJunk, g.GameType())
+		assert.Equal(t, PlayerNone, g.Declarer())
+	})
+
+	t.Run("rejects changes after dealing", func(t *testing.T) {
+		g := testGetBiddingPhaseGame(t)
+		assert.Equal(t, ErrWrongPhase, ScheduledGame{Multiplier: 2}.ApplyTo(g))
+		assert.Equal(t, ErrWrongPhase, g.ForceJunk())
+	})
+
+	t.Run("rejects invalid multiplier", func(t *testing.T) {
+		g := testGetInitPhaseGame(t)
+		assert.Equal(t, ErrInvalidFactor, g.SetMultiplier(0))
+	})
+}
@@ -0,0 +1,241 @@
+package skat
+
+import (
+	"errors"
+)
+
+const (
+	// Maximum number of positions examined when verifying a claim
+	claimSearchBudget = 200000
+)
+
+var (
+	ErrClaimUndecided = errors.New("claim could not be verified in time")
+)
+
+// A claim to take a number of the remaining tricks
+//
+// The tricks are counted for the side of the claiming player, i.e. a
+// defender claims on behalf of both defenders. In a Null game, the declarer
+// instead claims to take at most the given number of tricks.
+type Claim struct {
+	Player   int     `json:"player"`
+	Tricks   int     `json:"tricks"`
+	Accepted [3]bool `json:"accepted"`
+}
+
+type claimPosition struct {
+	hands   [3]CardSet
+	table   CardSet
+	leader  int
+	current int
+	// tricks taken by the claiming side since the claim
+	taken int
+	// number of tricks which have not been completed
+	left int
+}
+
+type claimMemoKey struct {
+	hands  [3]uint64
+	leader int
+	taken  int
+}
+
+type claimSolver struct {
+	gameType GameType
+	side     [3]bool
+	// if set, the claim must hold however either side plays
+	anyPlay bool
+	min     int
+	max     int
+	memo    map[claimMemoKey]bool
+	budget  int
+}
+
+func cardBit(c Card) uint64 {
+	return 1 << (uint(c.Suit)*10 + uint(c.Type))
+}
+
+func (s *PlayingState) claimPosition() claimPosition {
+	result := claimPosition{
+		table:   s.table.Copy(),
+		leader:  s.forehand,
+		current: s.current,
+	}
+	ncards := len(s.table)
+	for i := range s.players {
+		result.hands[i] = s.players[i].Hand.Copy()
+		ncards = ncards + len(s.players[i].Hand)
+	}
+	result.left = ncards / 3
+	return result
+}
+
+func (s *PlayingState) newClaimSolver(player int, tricks int) *claimSolver {
+	result := &claimSolver{
+		gameType: s.gameType,
+		min:      tricks,
+		max:      s.RemainingTricks(),
+		memo:     make(map[claimMemoKey]bool),
+		budget:   claimSearchBudget,
+	}
+	if s.gameType.IsNull() && player == s.declarer {
+		result.min = 0
+		result.max = tricks
+	}
+	for i := range result.side {
+		result.side[i] = (i == s.declarer) == (player == s.declarer)
+	}
+	return result
+}
+
+func (cs *claimSolver) play(p claimPosition, card Card) claimPosition {
+	result := p
+	result.hands[p.current], _ = p.hands[p.current].Pop(card)
+	result.table = append(p.table.Copy(), card)
+	if len(result.table) < 3 {
+		result.current = (p.current + 1) % 3
+		return result
+	}
+	trick := Trick{result.table[0], result.table[1], result.table[2]}
+	winner := (p.leader + trick.Taker(cs.gameType)) % 3
+	if cs.side[winner] {
+		result.taken = result.taken + 1
+	}
+	result.table = nil
+	result.leader = winner
+	result.current = winner
+	result.left = result.left - 1
+	return result
+}
+
+// Test whether the claiming side can reach its goal from the position,
+// no matter how the other side plays
+func (cs *claimSolver) search(p claimPosition) (bool, error) {
+	var key claimMemoKey
+	atBoundary := len(p.table) == 0
+	if atBoundary {
+		if p.taken > cs.max || p.taken+p.left < cs.min {
+			return false, nil
+		}
+		if p.taken >= cs.min && p.taken+p.left <= cs.max {
+			return true, nil
+		}
+		key = claimMemoKey{leader: p.leader, taken: p.taken}
+		for i, hand := range p.hands {
+			for _, card := range hand {
+				key.hands[i] = key.hands[i] | cardBit(card)
+			}
+		}
+		if result, ok := cs.memo[key]; ok {
+			return result, nil
+		}
+	}
+
+	cs.budget = cs.budget - 1
+	if cs.budget < 0 {
+		return false, ErrClaimUndecided
+	}
+
+	// the claiming side needs one good card, the other side must not have
+	// any good card
+	claiming := cs.side[p.current] && !cs.anyPlay
+	result := !claiming
+	for _, card := range legalCards(p.hands[p.current], p.table, cs.gameType) {
+		holds, err := cs.search(cs.play(p, card))
+		if err != nil {
+			return false, err
+		}
+		if holds == claiming {
+			result = claiming
+			break
+		}
+	}
+
+	if atBoundary {
+		cs.memo[key] = result
+	}
+	return result, nil
+}
+
+func (s *PlayingState) sideTrickCount(side [3]bool) int {
+	result := 0
+	for i := range s.players {
+		if side[i] {
+			result = result + s.players[i].Tricks
+		}
+	}
+	return result
+}
+
+// Return the number of tricks which have not been completed yet
+func (s *PlayingState) RemainingTricks() int {
+	return s.claimPosition().left
+}
+
+// Check whether a claim holds against every possible defence
+//
+// Returns ErrClaimUndecided if the check takes too long.
+func (s *PlayingState) VerifyClaim(player int, tricks int) (bool, error) {
+	return s.newClaimSolver(player, tricks).search(s.claimPosition())
+}
+
+// Check whether a claim holds however both sides play
+//
+// Such a claim concedes nothing to the claiming side. Returns
+// ErrClaimUndecided if the check takes too long.
+func (s *PlayingState) claimAlwaysHolds(player int, tricks int) (bool, error) {
+	solver := s.newClaimSolver(player, tricks)
+	solver.anyPlay = true
+	return solver.search(s.claimPosition())
+}
+
+// Play the remaining cards after a claim has been accepted
+//
+// The claiming side takes exactly the claimed tricks and the other side the
+// rest. The cards are played until the tricks of one side are settled; the
+// remaining cards then go to the other side. Until then, the claiming side
+// plays so that the claim holds if it can and the other side plays the first
+// card it is allowed to.
+func (s *PlayingState) PlayOutClaim(player int, tricks int) error {
+	solver := s.newClaimSolver(player, tricks)
+	initial := s.sideTrickCount(solver.side)
+	for {
+		p := s.claimPosition()
+		p.taken = s.sideTrickCount(solver.side) - initial
+		if p.taken == tricks {
+			s.awardClaim(player, false)
+			return nil
+		}
+		if p.taken+p.left == tricks {
+			s.awardClaim(player, true)
+			return nil
+		}
+		solver.budget = claimSearchBudget
+		legal := legalCards(p.hands[p.current], p.table, s.gameType)
+		card := legal[0]
+		if solver.side[p.current] {
+			for _, candidate := range legal {
+				holds, err := solver.search(solver.play(p, candidate))
+				if err == nil && holds {
+					card = candidate
+					break
+				}
+			}
+		}
+		if err := s.Play(p.current, card); err != nil {
+			return err
+		}
+	}
+}
+
+// Award the remaining cards to the claiming side or to the other side
+func (s *PlayingState) awardClaim(player int, toClaimingSide bool) {
+	if toClaimingSide {
+		s.AwardRemainingCards(player)
+	} else if player == s.declarer {
+		s.AwardRemainingCards((s.declarer + 1) % 3)
+	} else {
+		s.AwardRemainingCards(s.declarer)
+	}
+}
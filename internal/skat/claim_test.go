@@ -0,0 +1,155 @@
+package skat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Declarer (forehand) holds the top trump and a low club, middlehand holds
+// the other trump and the club ace. Whatever the declarer leads, each side
+// takes one trick.
+func testClaimPlayingState(gameType GameType) *PlayingState {
+	hands := [3]CardSet{
+		CardSet{CardJack.As(SuitClubs), Card7.As(SuitClubs)},
+		CardSet{CardJack.As(SuitSpades), CardAce.As(SuitClubs)},
+		CardSet{Card8.As(SuitClubs), Card9.As(SuitClubs)},
+	}
+	return NewPlayingState(
+		PlayerInitialForehand,
+		gameType,
+		[3]*CardSet{&hands[0], &hands[1], &hands[2]},
+		nil,
+	)
+}
+
+func TestLegalCards(t *testing.T) {
+	hand := CardSet{CardJack.As(SuitClubs), Card7.As(SuitClubs), CardAce.As(SuitHearts)}
+
+	t.Run("any card can be led", func(t *testing.T) {
+		assert.Equal(t, hand, legalCards(hand, nil, GameTypeHearts))
+	})
+
+	t.Run("must follow suit", func(t *testing.T) {
+		table := CardSet{CardKing.As(SuitClubs)}
+		assert.Equal(t, CardSet{Card7.As(SuitClubs)}, legalCards(hand, table, GameTypeHearts))
+	})
+
+	t.Run("jacks are trumps", func(t *testing.T) {
+		table := CardSet{Card8.As(SuitHearts)}
+		assert.Equal(t, CardSet{CardJack.As(SuitClubs), CardAce.As(SuitHearts)}, legalCards(hand, table, GameTypeHearts))
+	})
+
+	t.Run("any card if the suit cannot be followed", func(t *testing.T) {
+		table := CardSet{Card8.As(SuitSpades)}
+		assert.Equal(t, hand, legalCards(hand, table, GameTypeHearts))
+	})
+}
+
+func TestVerifyClaim(t *testing.T) {
+	t.Run("claim which the defence cannot prevent", func(t *testing.T) {
+		s := testClaimPlayingState(GameTypeHearts)
+		assert.Equal(t, 2, s.RemainingTricks())
+		holds, err := s.VerifyClaim(PlayerInitialForehand, 1)
+		assert.Nil(t, err)
+		assert.True(t, holds)
+	})
+
+	t.Run("claim which the defence can prevent", func(t *testing.T) {
+		s := testClaimPlayingState(GameTypeHearts)
+		holds, err := s.VerifyClaim(PlayerInitialForehand, 2)
+		assert.Nil(t, err)
+		assert.False(t, holds)
+	})
+
+	t.Run("defenders claim for both defenders", func(t *testing.T) {
+		s := testClaimPlayingState(GameTypeHearts)
+		holds, err := s.VerifyClaim(PlayerInitialRearhand, 1)
+		assert.Nil(t, err)
+		assert.True(t, holds)
+
+		holds, err = s.VerifyClaim(PlayerInitialRearhand, 2)
+		assert.Nil(t, err)
+		assert.False(t, holds)
+	})
+
+	t.Run("null declarer claims at most the given tricks", func(t *testing.T) {
+		s := testClaimPlayingState(GameTypeNull)
+		// the 7 of clubs loses against the ace and the jack of clubs can be
+		// discarded on the jack of spades
+		holds, err := s.VerifyClaim(PlayerInitialForehand, 0)
+		assert.Nil(t, err)
+		assert.True(t, holds)
+
+		holds, err = s.VerifyClaim(PlayerInitialForehand, 2)
+		assert.Nil(t, err)
+		assert.True(t, holds)
+	})
+
+	t.Run("claims which hold for any play", func(t *testing.T) {
+		s := testClaimPlayingState(GameTypeHearts)
+		// the top trump takes a trick whenever it is played
+		holds, err := s.claimAlwaysHolds(PlayerInitialForehand, 1)
+		assert.Nil(t, err)
+		assert.True(t, holds)
+		holds, err = s.claimAlwaysHolds(PlayerInitialForehand, 2)
+		assert.Nil(t, err)
+		assert.False(t, holds)
+		holds, err = s.claimAlwaysHolds(PlayerInitialRearhand, 0)
+		assert.Nil(t, err)
+		assert.True(t, holds)
+	})
+
+	t.Run("position after the first card", func(t *testing.T) {
+		s := testClaimPlayingState(GameTypeHearts)
+		assert.Nil(t, s.Play(PlayerInitialForehand, Card7.As(SuitClubs)))
+		holds, err := s.VerifyClaim(PlayerInitialMiddlehand, 2)
+		assert.Nil(t, err)
+		assert.False(t, holds)
+		holds, err = s.VerifyClaim(PlayerInitialMiddlehand, 1)
+		assert.Nil(t, err)
+		assert.True(t, holds)
+	})
+}
+
+func TestPlayOutClaim(t *testing.T) {
+	t.Run("awards all remaining cards", func(t *testing.T) {
+		s := testClaimPlayingState(GameTypeHearts)
+		assert.Nil(t, s.PlayOutClaim(PlayerInitialForehand, 1))
+		for i := 0; i < 3; i = i + 1 {
+			assert.Equal(t, 0, len(s.GetHand(i)))
+		}
+		assert.Equal(t, 0, s.RemainingTricks())
+		assert.Equal(t, 1, s.GetTrickCount(PlayerInitialForehand))
+		assert.Equal(t, 1, s.GetTrickCount(PlayerInitialMiddlehand)+s.GetTrickCount(PlayerInitialRearhand))
+	})
+
+	t.Run("accepted claim holds even if it cannot be shown", func(t *testing.T) {
+		s := testClaimPlayingState(GameTypeHearts)
+		assert.Nil(t, s.PlayOutClaim(PlayerInitialForehand, 2))
+		assert.Equal(t, 0, s.RemainingTricks())
+		assert.Equal(t, 2, s.GetTrickCount(PlayerInitialForehand))
+	})
+
+	t.Run("claiming side takes no more than claimed", func(t *testing.T) {
+		s := testPlayingState(t).s
+		side := s.Declarer() == PlayerInitialForehand
+		assert.Nil(t, s.PlayOutClaim(PlayerInitialForehand, 3))
+		assert.Equal(t, 0, s.RemainingTricks())
+		claimed := 0
+		for i := 0; i < 3; i = i + 1 {
+			if (i == s.Declarer()) == side {
+				claimed = claimed + s.GetTrickCount(i)
+			}
+		}
+		assert.Equal(t, 3, claimed)
+	})
+
+	t.Run("other side takes the rest", func(t *testing.T) {
+		s := testClaimPlayingState(GameTypeHearts)
+		assert.Nil(t, s.PlayOutClaim(PlayerInitialRearhand, 0))
+		assert.Equal(t, 0, s.RemainingTricks())
+		assert.Equal(t, 0, len(s.GetTrickHistory()))
+		assert.Equal(t, 2, s.GetTrickCount(PlayerInitialForehand))
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/sha512"
+	"crypto/subtle"
 	"errors"
 	"io"
 )
@@ -172,6 +174,70 @@ func DrawCards(deck CardSet, ncards int) (remainingCards CardSet, drawnCards Car
 	return remainingCards, drawnCards, nil
 }
 
+func dealRoundOfHands(deck CardSet, hands *[3]CardSet, n int) (CardSet, error) {
+	var err error
+	for i := range hands {
+		var dealt CardSet
+		deck, dealt, err = DrawCards(deck, n)
+		if err != nil {
+			return nil, err
+		}
+		hands[i] = append(hands[i], dealt...)
+	}
+	return deck, nil
+}
+
+// Deal a full deck in the order used at the table
+//
+// Each player gets three cards, then two cards go to the skat, then each
+// player gets four and finally three more cards.
+func DealDeck(deck CardSet) (hands [3]CardSet, skat CardSet, err error) {
+	deck, err = dealRoundOfHands(deck, &hands, 3)
+	if err != nil {
+		return hands, nil, err
+	}
+	deck, skat, err = DrawCards(deck, 2)
+	if err != nil {
+		return hands, nil, err
+	}
+	deck, err = dealRoundOfHands(deck, &hands, 4)
+	if err != nil {
+		return hands, nil, err
+	}
+	deck, err = dealRoundOfHands(deck, &hands, 3)
+	if err != nil {
+		return hands, nil, err
+	}
+	if len(deck) != 0 {
+		panic("too many cards in generated deck")
+	}
+	return hands, skat, nil
+}
+
+// Shuffle a fresh deck with the seed and deal it, see DealDeck
+func DealWithSeed(seed []byte) (hands [3]CardSet, skat CardSet, err error) {
+	deck := NewCardDeck()
+	if err := ShuffleDeckWithSeed(seed, &deck); err != nil {
+		return hands, nil, err
+	}
+	return DealDeck(deck)
+}
+
+// Return the commitment to a seed
+//
+// A party first publishes the commitment and reveals the seed only after all
+// other parties have committed, so that nobody can choose their seed based on
+// the seeds of the others.
+func CommitToSeed(seed []byte) []byte {
+	digest := sha256.Sum256(seed)
+	return digest[:]
+}
+
+// Check whether a revealed seed matches a commitment
+func VerifySeedCommitment(seed []byte, commitment []byte) bool {
+	return subtle.ConstantTimeCompare(CommitToSeed(seed), commitment) == 1
+}
+
 func GenerateSeed() ([]byte, error) {
 	seed := make([]byte, ServerSeedSize)
 	_, err := rand.Read(seed)
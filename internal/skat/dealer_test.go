@@ -160,3 +160,36 @@ func TestShuffleDeckWithSeed(t *testing.T) {
 		assert.Equal(t, Card{Card9, SuitClubs}, drawn[2])
 	})
 }
+
+func TestDealDeck(t *testing.T) {
+	t.Run("deals three, skat, four, three", func(t *testing.T) {
+		deck := NewCardDeck()
+		hands, skat, err := DealDeck(deck)
+		assert.Nil(t, err)
+		assert.Equal(t, deck[9:11], skat)
+		for i := range hands {
+			expected := CardSet{}
+			expected = append(expected, deck[3*i:3*i+3]...)
+			expected = append(expected, deck[11+4*i:15+4*i]...)
+			expected = append(expected, deck[23+3*i:26+3*i]...)
+			assert.Equal(t, expected, hands[i])
+		}
+	})
+
+	t.Run("rejects short decks", func(t *testing.T) {
+		_, _, err := DealDeck(NewCardDeck()[:20])
+		assert.Equal(t, ErrNotEnoughCards, err)
+	})
+
+	t.Run("matches the deal of a game", func(t *testing.T) {
+		g := testGetBiddingPhaseGame(t)
+		seed, err := g.ComposedSeed()
+		assert.Nil(t, err)
+		hands, skat, err := DealWithSeed(seed)
+		assert.Nil(t, err)
+		for i := range hands {
+			assert.Equal(t, g.GetHand(i), hands[i])
+		}
+		assert.Equal(t, g.dealtSkat, skat)
+	})
+}
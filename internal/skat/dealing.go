@@ -0,0 +1,66 @@
+package skat
+
+// A way to deal the cards at the start of a game, see RuleSet.Dealing
+type DealingStrategy interface {
+	// Deal the cards of the game
+	//
+	// The hands and the skat are nil if the cards are sealed. Returns an
+	// error such as ErrMissingSeed if the parties have not provided
+	// everything the deal needs yet.
+	Deal(g *GameState) (hands [3]CardSet, skat CardSet, err error)
+	// Return true if the server does not know the dealt cards
+	Sealed() bool
+}
+
+// Deal from a deck shuffled with the composed seed of all parties
+type SeededDealing struct{}
+
+func (d SeededDealing) Deal(g *GameState) (hands [3]CardSet, skat CardSet, err error) {
+	if err := g.verifySeeds(); err != nil {
+		return hands, nil, err
+	}
+	seed, err := g.ComposedSeed()
+	if err != nil {
+		return hands, nil, err
+	}
+	return DealWithSeed(seed)
+}
+
+func (d SeededDealing) Sealed() bool {
+	return false
+}
+
+// Let the players deal among themselves, see SealedDeal
+type MentalPokerDealing struct {
+	deal SealedDeal
+}
+
+func NewMentalPokerDealing() *MentalPokerDealing {
+	return &MentalPokerDealing{
+		deal: SealedDeal{
+			AwardedTo: PlayerNone,
+			Cheater:   PlayerNone,
+		},
+	}
+}
+
+// Returns ErrDealIncomplete until all cards have been shuffled and stripped
+// for their owners
+func (d *MentalPokerDealing) Deal(g *GameState) (hands [3]CardSet, skat CardSet, err error) {
+	if d.deal.Shuffled < len(d.deal.PublicKeys) || d.deal.NextStripper() != PlayerNone {
+		return hands, nil, ErrDealIncomplete
+	}
+	return hands, nil, nil
+}
+
+func (d *MentalPokerDealing) Sealed() bool {
+	return true
+}
+
+// Return the strategy for the dealing mode of the rules
+func NewDealingStrategy(mode DealingMode) DealingStrategy {
+	if mode == DealingMentalPoker {
+		return NewMentalPokerDealing()
+	}
+	return SeededDealing{}
+}
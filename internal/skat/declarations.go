@@ -0,0 +1,90 @@
+package skat
+
+// A game the declarer may announce
+type DeclarationOption struct {
+	GameType GameType `json:"gameType"`
+	// Modifiers to pass to Declare
+	Announced GameModifier `json:"announced"`
+	// Lowest value the game has if it is played out, not counting Schneider
+	// and Schwarz which have not been announced or Kontra and Re
+	MinimumValue int `json:"minimumValue"`
+	// The minimum value is below the called bid: unless the game reaches a
+	// higher value, it is lost as overbid
+	Overbid bool `json:"overbid"`
+}
+
+// Return the lowest value of the game over all possible skats
+//
+// In a Hand game, the skat is unknown to the declarer but still counts for
+// the matadors.
+func (r *RuleSet) minimumGameValue(hand CardSet, gameType GameType, modifiers GameModifier) int {
+	if len(hand) != 10 {
+		base, factor := r.GameValue(hand, gameType, modifiers)
+		return base * factor
+	}
+
+	others := make(CardSet, 0, 22)
+	for _, card := range NewCardDeck() {
+		if !hand.Contains(card) {
+			others = append(others, card)
+		}
+	}
+	result := -1
+	full := append(hand.Copy(), Card{}, Card{})
+	for i := range others {
+		for j := i + 1; j < len(others); j = j + 1 {
+			full[10] = others[i]
+			full[11] = others[j]
+			base, factor := r.GameValue(full, gameType, modifiers)
+			if result < 0 || base*factor < result {
+				result = base * factor
+			}
+		}
+	}
+	return result
+}
+
+// Return all games the declarer may announce
+//
+// The hand contains the cards of the declarer, including the skat if it has
+// been taken. The modifiers are those the game has before the declaration,
+// i.e. GameModifierHand as long as the skat has not been taken. Announcements
+// which are implied by others are not listed separately. Ouvert games are
+// not listed if the rules do not allow them.
+func (r *RuleSet) EnumerateDeclarations(hand CardSet, bid int, modifiers GameModifier) []DeclarationOption {
+	gameTypes := StandardGameTypes
+	if r.Revolution {
+		gameTypes = append(gameTypes[:len(gameTypes):len(gameTypes)], GameTypeRevolution)
+	}
+
+	result := make([]DeclarationOption, 0)
+	for _, gameType := range gameTypes {
+		seen := make(map[GameModifier]bool)
+		for announced := NoGameModifiers; announced <= AnnouncementModifiers; announced = announced + 1 {
+			if !announced.IsAnnounceable() {
+				continue
+			}
+			newModifiers := r.NormalizedModifiers(modifiers|announced, gameType)
+			if seen[newModifiers] || !r.ValidModifiers(newModifiers, gameType) {
+				continue
+			}
+			if newModifiers.Test(GameModifierOuvert) && !r.OuvertAllowed() {
+				continue
+			}
+			seen[newModifiers] = true
+			value := r.minimumGameValue(hand, gameType, newModifiers)
+			result = append(result, DeclarationOption{
+				GameType:     gameType,
+				Announced:    announced,
+				MinimumValue: value,
+				Overbid:      value < bid,
+			})
+		}
+	}
+	return result
+}
+
+// Return all games the declarer may announce under the standard rules
+func EnumerateDeclarations(hand CardSet, bid int, modifiers GameModifier) []DeclarationOption {
+	return standardRules.EnumerateDeclarations(hand, bid, modifiers)
+}
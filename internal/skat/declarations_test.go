@@ -0,0 +1,113 @@
+package skat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testFindDeclaration(options []DeclarationOption, gameType GameType, announced GameModifier) *DeclarationOption {
+	for i := range options {
+		if options[i].GameType == gameType && options[i].Announced == announced {
+			return &options[i]
+		}
+	}
+	return nil
+}
+
+func TestEnumerateDeclarations(t *testing.T) {
+	jacksHand := CardSet{
+		CardJack.As(SuitClubs),
+		CardJack.As(SuitSpades),
+		CardAce.As(SuitHearts),
+		Card10.As(SuitHearts),
+		CardKing.As(SuitHearts),
+		CardQueen.As(SuitHearts),
+		Card7.As(SuitHearts),
+		CardAce.As(SuitDiamonds),
+		Card10.As(SuitSpades),
+		Card8.As(SuitClubs),
+	}
+	noJacksHand := CardSet{
+		CardAce.As(SuitClubs),
+		Card10.As(SuitClubs),
+		CardKing.As(SuitClubs),
+		CardAce.As(SuitSpades),
+		Card10.As(SuitSpades),
+		CardKing.As(SuitSpades),
+		CardAce.As(SuitHearts),
+		Card10.As(SuitHearts),
+		CardAce.As(SuitDiamonds),
+		Card10.As(SuitDiamonds),
+	}
+
+	t.Run("announcements only in hand games", func(t *testing.T) {
+		skat := CardSet{Card7.As(SuitDiamonds), Card8.As(SuitDiamonds)}
+		options := EnumerateDeclarations(append(jacksHand.Copy(), skat...), 18, NoGameModifiers)
+		assert.Equal(t, 7, len(options))
+		assert.NotNil(t, testFindDeclaration(options, GameTypeNull, GameModifierOuvert))
+		assert.Nil(t, testFindDeclaration(options, GameTypeGrand, GameModifierSchneiderAnnounced))
+
+		options = EnumerateDeclarations(jacksHand, 18, GameModifierHand)
+		assert.Equal(t, 22, len(options))
+		assert.NotNil(t, testFindDeclaration(options, GameTypeGrand, GameModifierSchneiderAnnounced))
+		assert.NotNil(t, testFindDeclaration(options, GameTypeGrand, GameModifierOuvert))
+		// implied by ouvert
+		assert.Nil(t, testFindDeclaration(options, GameTypeGrand, GameModifierOuvert|GameModifierSchneiderAnnounced))
+	})
+
+	t.Run("minimum value with the skat", func(t *testing.T) {
+		skat := CardSet{Card7.As(SuitDiamonds), Card8.As(SuitDiamonds)}
+		hand := append(jacksHand.Copy(), skat...)
+		option := testFindDeclaration(EnumerateDeclarations(hand, 72, NoGameModifiers), GameTypeGrand, NoGameModifiers)
+		assert.Equal(t, 72, option.MinimumValue)
+		assert.False(t, option.Overbid)
+		option = testFindDeclaration(EnumerateDeclarations(hand, 77, NoGameModifiers), GameTypeGrand, NoGameModifiers)
+		assert.True(t, option.Overbid)
+	})
+
+	t.Run("minimum value of a hand game assumes the worst skat", func(t *testing.T) {
+		// without 4, unless the skat holds the jack of clubs
+		option := testFindDeclaration(EnumerateDeclarations(noJacksHand, 18, GameModifierHand), GameTypeGrand, NoGameModifiers)
+		assert.Equal(t, 72, option.MinimumValue)
+	})
+
+	t.Run("null values", func(t *testing.T) {
+		options := EnumerateDeclarations(noJacksHand, 46, GameModifierHand)
+		option := testFindDeclaration(options, GameTypeNull, NoGameModifiers)
+		assert.Equal(t, 35, option.MinimumValue)
+		assert.True(t, option.Overbid)
+		option = testFindDeclaration(options, GameTypeNull, GameModifierOuvert)
+		assert.Equal(t, 59, option.MinimumValue)
+		assert.False(t, option.Overbid)
+	})
+
+	t.Run("revolution only with the house rule", func(t *testing.T) {
+		assert.Nil(t, testFindDeclaration(EnumerateDeclarations(noJacksHand, 18, GameModifierHand), GameTypeRevolution, NoGameModifiers))
+		rules := StandardRuleSet()
+		rules.Revolution = true
+		option := testFindDeclaration(rules.EnumerateDeclarations(noJacksHand, 18, GameModifierHand), GameTypeRevolution, NoGameModifiers)
+		assert.NotNil(t, option)
+		assert.Equal(t, 92, option.MinimumValue)
+		assert.Nil(t, testFindDeclaration(rules.EnumerateDeclarations(noJacksHand, 18, NoGameModifiers), GameTypeRevolution, NoGameModifiers))
+	})
+
+	t.Run("no ouvert with a sealed deal", func(t *testing.T) {
+		rules := testSealedRuleSet()
+		options := rules.EnumerateDeclarations(jacksHand, 18, GameModifierHand)
+		assert.Equal(t, 16, len(options))
+		for _, option := range options {
+			assert.False(t, rules.NormalizedModifiers(option.Announced, option.GameType).Test(GameModifierOuvert))
+		}
+		assert.NotNil(t, testFindDeclaration(options, GameTypeNull, NoGameModifiers))
+		assert.NotNil(t, testFindDeclaration(options, GameTypeGrand, GameModifierSchneiderAnnounced))
+	})
+
+	t.Run("every option can be declared", func(t *testing.T) {
+		hand := testGetDeclarationPhaseGame(t).GetHand(PlayerInitialMiddlehand)
+		for _, option := range EnumerateDeclarations(hand, 18, GameModifierHand) {
+			g := testGetDeclarationPhaseGame(t)
+			assert.Nil(t, g.Declare(PlayerInitialMiddlehand, option.GameType, option.Announced, nil))
+		}
+	})
+}
@@ -5,14 +5,32 @@ import (
 )
 
 var (
-	ErrMissingSeed     = errors.New("not all players have submitted a seed")
-	ErrWrongPhase      = errors.New("wrong game phase for this action")
-	ErrNotYourTurn     = errors.New("this is not your turn")
-	ErrBiddingNotDone  = errors.New("bidding has not completed yet")
-	ErrInvalidGameType = errors.New("invalid game type")
-	ErrNotImplemented  = errors.New("not implemented")
-	ErrInvalidGame     = errors.New("invalid game")
-	ErrInvalidPush     = errors.New("invalid push request")
+	ErrMissingSeed       = errors.New("not all players have submitted a seed")
+	ErrMissingCommitment = errors.New("not all parties have committed to a seed")
+	ErrSeedCommitted     = errors.New("commitment has already been submitted")
+	ErrInvalidCommitment = errors.New("invalid seed commitment")
+	ErrSeedMismatch      = errors.New("seed does not match its commitment")
+	ErrWrongPhase        = errors.New("wrong game phase for this action")
+	ErrNotYourTurn       = errors.New("this is not your turn")
+	ErrBiddingNotDone    = errors.New("bidding has not completed yet")
+	ErrInvalidGameType   = errors.New("invalid game type")
+	ErrNotImplemented    = errors.New("not implemented")
+	ErrInvalidGame       = errors.New("invalid game")
+	ErrInvalidPush       = errors.New("invalid push request")
+	ErrTooLateToDouble   = errors.New("too late for kontra or re")
+	ErrAlreadyDoubled    = errors.New("kontra or re has already been given")
+	ErrNoKontra          = errors.New("re requires a kontra")
+	ErrInvalidFactor     = errors.New("invalid multiplier")
+	ErrAlreadyResigned   = errors.New("player has already resigned")
+	ErrNothingToPeek     = errors.New("nothing to peek at")
+	ErrClaimPending      = errors.New("a claim is pending")
+	ErrNoClaim           = errors.New("no claim is pending")
+	ErrInvalidClaim      = errors.New("invalid claim")
+	ErrNoDealer          = errors.New("game is played without a dealer")
+	ErrInvalidPlayer     = errors.New("no such player")
+	ErrAlreadyWatching   = errors.New("dealer is already watching a hand")
+	ErrInvalidExchange   = errors.New("invalid exchange of cards")
+	ErrAlreadyExchanged  = errors.New("player has already exchanged cards")
 )
 
 const (
@@ -39,28 +57,78 @@ const (
 
 	// All cards have been played in one way or another, game has been scored
 	PhaseScored GamePhase = 4
+
+	// All players passed and the game ended without a score, see
+	// AllPassRedeal
+	PhasePassedIn GamePhase = 5
+
+	// Junk game is about to start, players take the skat and push it on in
+	// turn, see RuleSet.Schieberamsch
+	PhaseJunkPushing GamePhase = 6
+
+	// Revolution has been declared, the defenders exchange cards before
+	// play starts
+	PhaseExchange GamePhase = 7
+
+	// All cards of a sealed deal have been played, the players reveal their
+	// keys for the audit, see SealedDeal
+	PhaseAudit GamePhase = 8
 )
 
 const (
 	ServerSeedSize = 16
 )
 
+type PeekTarget string
+
+const (
+	// The most recently completed trick
+	PeekLastTrick PeekTarget = "last_trick"
+
+	// The cards pushed by the declarer
+	PeekSkat PeekTarget = "skat"
+)
+
+// The decision of a player in Schieberamsch
+type SkatPass struct {
+	Player int `json:"player"`
+	// True if the player took the skat and pushed two cards, false if the
+	// skat was passed on untouched
+	Pushed bool `json:"pushed"`
+}
+
 type CommonPlayerState struct {
-	Seed     []byte
-	Hand     CardSet
-	WonCards CardSet
-	Score    int
+	SeedCommitment []byte
+	Seed           []byte
+	Hand           CardSet
+	// Hand as dealt, before any skat was taken or cards were exchanged
+	DealtHand CardSet
+	WonCards  CardSet
+	Score     int
+	Resigned  bool
+	// Defender is done exchanging cards in Revolution
+	Exchanged bool
+
+	// Peeks are valid until the next card is played
+	PeekingLastTrick bool
+	PeekingSkat      bool
 }
 
 type GameState struct {
 	phase               GamePhase
 	withDealer          bool
 	serverSeed          []byte
+	serverCommitment    []byte
 	dealerSeed          []byte
+	dealerCommitment    []byte
 	dealerLookingAtHand int
 	scoring             ScoreDefinition
+	rules               RuleSet
+	dealing             DealingStrategy
 
 	skat       CardSet
+	dealtSkat  CardSet
+	pushed     CardSet
 	players    [3]CommonPlayerState
 	modifiers  GameModifier
 	lossReason string
@@ -70,9 +138,27 @@ type GameState struct {
 
 	jackStrength   int
 	finalGameValue int
+
+	kontraPlayer int
+	// number of cards the declarer held when Kontra was given; Re is only
+	// possible until the declarer plays another card
+	kontraDeclarerCards int
+
+	// Bock rounds and forced Junk games, see BockScheduler
+	multiplier int
+	forceJunk  bool
+
+	claim *Claim
+
+	// Schieberamsch
+	skatPasses []SkatPass
+	skatTaken  bool
 }
 
-func NewGame(withDealer bool, scoring *ScoreDefinition) (*GameState, error) {
+func NewGame(withDealer bool, scoring *ScoreDefinition, rules *RuleSet) (*GameState, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
 	seed, err := GenerateSeed()
 	if err != nil {
 		return nil, err
@@ -82,8 +168,13 @@ func NewGame(withDealer bool, scoring *ScoreDefinition) (*GameState, error) {
 		phase:               PhaseInit,
 		dealerLookingAtHand: PlayerNone,
 		scoring:             *scoring,
+		rules:               *rules,
+		dealing:             NewDealingStrategy(rules.Dealing),
 		modifiers:           GameModifierHand,
 		serverSeed:          seed,
+		serverCommitment:    CommitToSeed(seed),
+		kontraPlayer:        PlayerNone,
+		multiplier:          1,
 	}, nil
 }
 
@@ -91,125 +182,280 @@ func (g *GameState) Phase() GamePhase {
 	return g.phase
 }
 
-func (g *GameState) SetSeed(player int, seed Seed) error {
+func (g *GameState) Rules() *RuleSet {
+	rules := g.rules
+	return &rules
+}
+
+// Set the factor by which the score of this game is multiplied
+//
+// This is used for Bock rounds and must happen before the cards are dealt.
+func (g *GameState) SetMultiplier(multiplier int) error {
 	if g.phase != PhaseInit {
 		return ErrWrongPhase
 	}
-	g.players[player].Seed = seed
-	err := g.Deal()
-	if err != ErrMissingSeed {
-		return err
+	if multiplier < 1 {
+		return ErrInvalidFactor
 	}
+	g.multiplier = multiplier
 	return nil
 }
 
-func (g *GameState) SetDealerSeed(seed Seed) error {
+func (g *GameState) Multiplier() int {
+	return g.multiplier
+}
+
+// Return the multiplier and whether Junk is forced, as set before dealing
+func (g *GameState) Schedule() ScheduledGame {
+	return ScheduledGame{
+		Multiplier: g.multiplier,
+		Junk:       g.forceJunk,
+	}
+}
+
+func (g *GameState) Scoring() *ScoreDefinition {
+	scoring := g.scoring
+	return &scoring
+}
+
+// Skip bidding and play Junk right after dealing
+//
+// This is used for Junk rounds and must happen before the cards are dealt.
+func (g *GameState) ForceJunk() error {
 	if g.phase != PhaseInit {
 		return ErrWrongPhase
 	}
-	if g.withDealer {
-		g.dealerSeed = seed
-	}
+	g.forceJunk = true
+	return nil
+}
+
+// Deal if all seeds have been revealed
+func (g *GameState) dealIfReady() error {
 	err := g.Deal()
-	if err != ErrMissingSeed {
+	if err != ErrMissingSeed && err != ErrMissingCommitment && err != ErrDealIncomplete {
 		return err
 	}
 	return nil
 }
 
-func (g *GameState) ServerSeed() Seed {
-	return g.serverSeed
+func checkCommitment(commitment []byte) error {
+	if len(commitment) != len(CommitToSeed(nil)) {
+		return ErrInvalidCommitment
+	}
+	return nil
 }
 
-func (g *GameState) ForceServerSeed(seed Seed) error {
+// Commit to the seed the player will reveal with SetSeed
+//
+// The commitment cannot be changed once submitted.
+func (g *GameState) CommitSeed(player int, commitment []byte) error {
 	if g.phase != PhaseInit {
 		return ErrWrongPhase
 	}
-	g.serverSeed = seed
-	err := g.Deal()
-	if err != ErrMissingSeed {
+	if err := checkCommitment(commitment); err != nil {
 		return err
 	}
+	if g.players[player].SeedCommitment != nil {
+		return ErrSeedCommitted
+	}
+	g.players[player].SeedCommitment = commitment
 	return nil
 }
 
-func (g *GameState) ComposedSeed() ([]byte, error) {
-	if g.serverSeed == nil {
-		return nil, ErrMissingSeed
+// Commit to the seed the dealer will reveal with SetDealerSeed
+func (g *GameState) CommitDealerSeed(commitment []byte) error {
+	if g.phase != PhaseInit {
+		return ErrWrongPhase
+	}
+	if !g.withDealer {
+		return ErrNoDealer
+	}
+	if err := checkCommitment(commitment); err != nil {
+		return err
+	}
+	if g.dealerCommitment != nil {
+		return ErrSeedCommitted
 	}
-	result := make([]byte, len(g.serverSeed))
-	copy(result, g.serverSeed)
+	g.dealerCommitment = commitment
+	return nil
+}
+
+// Return true once every party has committed to a seed
+//
+// Seeds can only be revealed from then on. The server seed is revealed at the
+// same time.
+func (g *GameState) SeedsCommitted() bool {
 	for _, player := range g.players {
-		if player.Seed == nil {
-			return nil, ErrMissingSeed
+		if player.SeedCommitment == nil {
+			return false
 		}
-		result = append(result, player.Seed...)
 	}
-	if g.withDealer && g.dealerSeed == nil {
-		return nil, ErrMissingSeed
+	if g.withDealer && g.dealerCommitment == nil {
+		return false
 	}
-	result = append(result, g.dealerSeed...)
-	return result, nil
+	return true
 }
 
-func (g *GameState) dealRoundOfHands(cards []Card, n int) ([]Card, error) {
-	var err error
-	for i := 0; i < 3; i = i + 1 {
-		var dealt []Card
-		cards, dealt, err = DrawCards(cards, n)
-		if err != nil {
-			return nil, err
+// Reveal the seed of a player
+//
+// All parties must have committed to their seeds before and the seed must
+// match the commitment of the player.
+func (g *GameState) SetSeed(player int, seed Seed) error {
+	if g.phase != PhaseInit {
+		return ErrWrongPhase
+	}
+	if !g.SeedsCommitted() {
+		return ErrMissingCommitment
+	}
+	if !VerifySeedCommitment(seed, g.players[player].SeedCommitment) {
+		return ErrSeedMismatch
+	}
+	g.players[player].Seed = seed
+	return g.dealIfReady()
+}
+
+// Reveal the seed of the dealer, see SetSeed
+func (g *GameState) SetDealerSeed(seed Seed) error {
+	if g.phase != PhaseInit {
+		return ErrWrongPhase
+	}
+	if !g.SeedsCommitted() {
+		return ErrMissingCommitment
+	}
+	if g.withDealer {
+		if !VerifySeedCommitment(seed, g.dealerCommitment) {
+			return ErrSeedMismatch
 		}
-		g.players[i].Hand = append(g.players[i].Hand, dealt...)
+		g.dealerSeed = seed
 	}
+	return g.dealIfReady()
+}
 
-	return cards, nil
+// Return true if a fourth player deals and sits out this game
+func (g *GameState) WithDealer() bool {
+	return g.withDealer
 }
 
-// Transition PhaseInit -> PhaseBidding
-func (g *GameState) Deal() error {
-	if g.phase != PhaseInit {
+// Let the dealer look at the hand of a player
+//
+// Once the declarer is known, the dealer may watch the hand of the declarer
+// or of one of the defenders for the rest of the game.
+func (g *GameState) WatchHand(player int) error {
+	if !g.withDealer {
+		return ErrNoDealer
+	}
+	if g.phase != PhaseDeclaration && g.phase != PhasePlaying {
 		return ErrWrongPhase
 	}
+	if g.Declarer() == PlayerNone {
+		return ErrInvalidGame
+	}
+	if g.dealing.Sealed() {
+		return ErrSealedDeal
+	}
+	if player < 0 || player >= len(g.players) {
+		return ErrInvalidPlayer
+	}
+	if g.dealerLookingAtHand != PlayerNone {
+		return ErrAlreadyWatching
+	}
+	g.dealerLookingAtHand = player
+	return nil
+}
 
-	seed, err := g.ComposedSeed()
-	if err != nil {
-		return err
+func (g *GameState) DealerLookingAtHand() int {
+	return g.dealerLookingAtHand
+}
+
+func (g *GameState) ServerSeed() Seed {
+	return g.serverSeed
+}
+
+func (g *GameState) ServerCommitment() []byte {
+	return g.serverCommitment
+}
+
+// Replace the generated server seed
+//
+// This is only possible before anybody has committed to a seed.
+func (g *GameState) ForceServerSeed(seed Seed) error {
+	if g.phase != PhaseInit {
+		return ErrWrongPhase
+	}
+	if g.dealerCommitment != nil {
+		return ErrSeedCommitted
+	}
+	for _, player := range g.players {
+		if player.SeedCommitment != nil {
+			return ErrSeedCommitted
+		}
 	}
+	g.serverSeed = seed
+	g.serverCommitment = CommitToSeed(seed)
+	return g.dealIfReady()
+}
 
-	deck := NewCardDeck()
-	err = ShuffleDeckWithSeed(seed, &deck)
-	if err != nil {
-		return err
+func (g *GameState) ComposedSeed() ([]byte, error) {
+	var playerSeeds [3][]byte
+	for i, player := range g.players {
+		playerSeeds[i] = player.Seed
 	}
+	return composeSeed(g.serverSeed, playerSeeds, g.withDealer, g.dealerSeed)
+}
 
-	deck, err = g.dealRoundOfHands(deck, 3)
-	if err != nil {
-		return err
+// Check that every revealed seed matches its commitment
+func (g *GameState) verifySeeds() error {
+	if !g.SeedsCommitted() {
+		return ErrMissingCommitment
 	}
-	deck, g.skat, err = DrawCards(deck, 2)
-	if err != nil {
-		return err
+	if !VerifySeedCommitment(g.serverSeed, g.serverCommitment) {
+		return ErrSeedMismatch
 	}
-	deck, err = g.dealRoundOfHands(deck, 4)
-	if err != nil {
-		return err
+	for _, player := range g.players {
+		if player.Seed != nil && !VerifySeedCommitment(player.Seed, player.SeedCommitment) {
+			return ErrSeedMismatch
+		}
 	}
-	deck, err = g.dealRoundOfHands(deck, 3)
+	if g.withDealer && g.dealerSeed != nil && !VerifySeedCommitment(g.dealerSeed, g.dealerCommitment) {
+		return ErrSeedMismatch
+	}
+	return nil
+}
+
+// Transition PhaseInit -> PhaseBidding
+//
+// Requires that the dealing strategy has everything it needs, see
+// DealingStrategy.Deal. With a sealed deal, the hands stay unknown until the
+// audit.
+func (g *GameState) Deal() error {
+	if g.phase != PhaseInit {
+		return ErrWrongPhase
+	}
+
+	hands, skat, err := g.dealing.Deal(g)
 	if err != nil {
 		return err
 	}
-	if len(deck) != 0 {
-		panic("too many cards in generated deck")
+	if !g.dealing.Sealed() {
+		for i := range g.players {
+			g.players[i].Hand = hands[i]
+			g.players[i].DealtHand = hands[i].Copy()
+		}
+		g.skat = skat
+		g.dealtSkat = g.skat.Copy()
 	}
 
 	g.initBidding()
+	if g.forceJunk {
+		// nobody gets to bid, which is the same as everyone passing
+		g.startJunk()
+	}
 	return nil
 }
 
 func (g *GameState) initBidding() {
 	g.phase = PhaseBidding
-	g.biddingState = NewBiddingState()
+	g.biddingState = NewBiddingStateForRules(&g.rules)
 }
 
 /* func (g *GameState) Bidding() *BiddingState {
@@ -250,6 +496,9 @@ func (g *GameState) RespondToBid(player int, hold bool) error {
 }
 
 // Transition PhaseBidding -> PhaseDeclaration
+//
+// If all players passed, a Junk game is started instead (transition
+// PhaseBidding -> PhasePlaying).
 func (g *GameState) ConcludeBidding() error {
 	if g.phase != PhaseBidding {
 		return ErrWrongPhase
@@ -258,8 +507,15 @@ func (g *GameState) ConcludeBidding() error {
 		return ErrBiddingNotDone
 	}
 
-	// TODO: if nobody won the bidding, either transition to Junk or abort
-	// game
+	if g.biddingState.Declarer() == PlayerNone {
+		if g.rules.AllPass == AllPassRedeal {
+			g.phase = PhasePassedIn
+			return nil
+		}
+		g.startJunk()
+		return nil
+	}
+
 	g.initDeclaring()
 	return nil
 }
@@ -268,7 +524,114 @@ func (g *GameState) initDeclaring() {
 	g.phase = PhaseDeclaration
 }
 
+func (g *GameState) startJunk() {
+	if g.rules.Schieberamsch {
+		g.phase = PhaseJunkPushing
+		g.modifiers = NoGameModifiers
+		g.skatPasses = make([]SkatPass, 0, 3)
+		return
+	}
+	g.initJunk()
+}
+
+// Return the player who decides on the skat in Schieberamsch
+//
+// Returns PlayerNone outside of PhaseJunkPushing.
+func (g *GameState) JunkPushingPlayer() int {
+	if g.phase != PhaseJunkPushing {
+		return PlayerNone
+	}
+	return len(g.skatPasses)
+}
+
+func (g *GameState) concludeSkatPass(pushed bool) {
+	g.skatPasses = append(g.skatPasses, SkatPass{
+		Player: g.JunkPushingPlayer(),
+		Pushed: pushed,
+	})
+	g.skatTaken = false
+	if len(g.skatPasses) == len(g.players) {
+		g.initJunk()
+	}
+}
+
+// Take the skat in Schieberamsch and push two cards on
+//
+// The skat is taken first unless the player already did so with TakeSkat.
+// Jacks must not be pushed. Each push doubles the result of the game.
+func (g *GameState) PushSkatOn(player int, cardsToPush CardSet) error {
+	if g.phase != PhaseJunkPushing {
+		return ErrWrongPhase
+	}
+	if player != g.JunkPushingPlayer() {
+		return ErrNotYourTurn
+	}
+	if len(cardsToPush) != 2 {
+		return ErrInvalidPush
+	}
+	newHand := g.players[player].Hand.Copy()
+	if !g.skatTaken {
+		newHand = append(newHand, g.skat...)
+	}
+	for _, card := range cardsToPush {
+		if card.Type == CardJack {
+			return ErrInvalidPush
+		}
+		var err error
+		newHand, err = newHand.Pop(card)
+		if err != nil {
+			return ErrInvalidPush
+		}
+	}
+	g.players[player].Hand = newHand
+	g.skat = cardsToPush.Copy()
+	g.concludeSkatPass(true)
+	return nil
+}
+
+// Pass the skat on in Schieberamsch without looking at it
+func (g *GameState) PassSkatOn(player int) error {
+	if g.phase != PhaseJunkPushing {
+		return ErrWrongPhase
+	}
+	if player != g.JunkPushingPlayer() {
+		return ErrNotYourTurn
+	}
+	if g.skatTaken {
+		return ErrInvalidPush
+	}
+	g.concludeSkatPass(false)
+	return nil
+}
+
+// Return the number of times the skat was pushed on in Schieberamsch
+func (g *GameState) JunkPushes() int {
+	result := 0
+	for _, pass := range g.skatPasses {
+		if pass.Pushed {
+			result = result + 1
+		}
+	}
+	return result
+}
+
+func (g *GameState) initJunk() {
+	g.phase = PhasePlaying
+	g.modifiers = NoGameModifiers
+	g.playingState = NewJunkPlayingState(
+		[3]*CardSet{
+			&g.players[0].Hand,
+			&g.players[1].Hand,
+			&g.players[2].Hand,
+		},
+		g.skat,
+	)
+}
+
 func (g *GameState) TakeSkat(player int) error {
+	if g.phase == PhaseJunkPushing {
+		return g.takeJunkSkat(player)
+	}
 	if g.phase != PhaseDeclaration {
 		return ErrWrongPhase
 	}
@@ -280,35 +643,72 @@ func (g *GameState) TakeSkat(player int) error {
 		return ErrWrongPhase
 	}
 	g.modifiers = g.modifiers.Without(GameModifierHand)
+	if d := g.SealedDeal(); d != nil {
+		// the defenders strip the skat for the declarer before they can
+		// push
+		d.Hands[declarer] = append(d.Hands[declarer], d.Skat...)
+		d.Skat = nil
+		return nil
+	}
 	g.players[declarer].Hand = append(g.players[declarer].Hand, g.skat...)
 	return nil
 }
 
-func (g *GameState) Declare(player int, gameType GameType, announcedModifiers GameModifier, cardsToPush CardSet) error {
-	if g.phase != PhaseDeclaration {
+func (g *GameState) takeJunkSkat(player int) error {
+	if player != g.JunkPushingPlayer() {
+		return ErrNotYourTurn
+	}
+	if g.skatTaken {
 		return ErrWrongPhase
 	}
+	g.skatTaken = true
+	g.players[player].Hand = append(g.players[player].Hand, g.skat...)
+	return nil
+}
+
+// Check a declaration and return the modifiers of the declared game
+func (g *GameState) checkDeclaration(player int, gameType GameType, announcedModifiers GameModifier, npushed int) (GameModifier, error) {
+	if g.phase != PhaseDeclaration {
+		return NoGameModifiers, ErrWrongPhase
+	}
 	declarer := g.biddingState.Declarer()
 	if declarer != player {
-		return ErrNotYourTurn
+		return NoGameModifiers, ErrNotYourTurn
 	}
 	if !announcedModifiers.IsAnnounceable() {
-		return ErrInvalidGame
+		return NoGameModifiers, ErrInvalidGame
 	}
-	newModifiers := g.modifiers | announcedModifiers
-	if !newModifiers.ValidForGame(gameType) {
-		return ErrInvalidGame
+	if gameType == GameTypeRevolution && !g.rules.Revolution {
+		return NoGameModifiers, ErrRuleDisabled
+	}
+	newModifiers := g.rules.NormalizedModifiers(g.modifiers|announcedModifiers, gameType)
+	if !g.rules.ValidModifiers(newModifiers, gameType) {
+		return NoGameModifiers, ErrInvalidGame
 	}
 
-	if !g.modifiers.Test(GameModifierHand) && len(cardsToPush) != 2 {
-		return ErrInvalidPush
+	if !g.modifiers.Test(GameModifierHand) && npushed != 2 {
+		return NoGameModifiers, ErrInvalidPush
 	}
-	if g.modifiers.Test(GameModifierHand) && len(cardsToPush) != 0 {
-		return ErrInvalidPush
+	if g.modifiers.Test(GameModifierHand) && npushed != 0 {
+		return NoGameModifiers, ErrInvalidPush
+	}
+	return newModifiers, nil
+}
+
+func (g *GameState) Declare(player int, gameType GameType, announcedModifiers GameModifier, cardsToPush CardSet) error {
+	if g.dealing.Sealed() {
+		// sealed cards can only be pushed by slot
+		if len(cardsToPush) > 0 {
+			return ErrSealedDeal
+		}
+		return g.DeclareSealed(player, gameType, announcedModifiers, nil)
+	}
+	newModifiers, err := g.checkDeclaration(player, gameType, announcedModifiers, len(cardsToPush))
+	if err != nil {
+		return err
 	}
 	newHand := g.players[player].Hand
 	for _, card := range cardsToPush {
-		var err error
 		newHand, err = newHand.Pop(card)
 		if err != nil {
 			return ErrInvalidPush
@@ -318,14 +718,26 @@ func (g *GameState) Declare(player int, gameType GameType, announcedModifiers Ga
 	var skatCards CardSet
 	if len(cardsToPush) > 0 {
 		skatCards = cardsToPush
+		g.pushed = cardsToPush.Copy()
 	} else {
 		skatCards = g.skat
 	}
 
 	g.players[player].Hand = newHand
+	g.modifiers = newModifiers
+	if gameType == GameTypeRevolution {
+		g.phase = PhaseExchange
+		return nil
+	}
+	g.startPlaying(gameType, skatCards)
+	return nil
+}
+
+func (g *GameState) startPlaying(gameType GameType, skatCards CardSet) {
+	player := g.biddingState.Declarer()
 	g.phase = PhasePlaying
 	g.playingState = NewPlayingState(
-		g.biddingState.Declarer(),
+		player,
 		gameType,
 		[3]*CardSet{
 			&g.players[0].Hand,
@@ -334,6 +746,10 @@ func (g *GameState) Declare(player int, gameType GameType, announcedModifiers Ga
 		},
 		skatCards,
 	)
+	if g.dealing.Sealed() {
+		// the hands and the skat are only known after the audit
+		return
+	}
 	// now for the declarer, we add the skat to the hand for post-game
 	// evaluation
 	g.players[player].Hand, _ = g.players[player].Hand.Push(
@@ -342,6 +758,53 @@ func (g *GameState) Declare(player int, gameType GameType, announcedModifiers Ga
 	g.players[player].Hand, _ = g.players[player].Hand.Push(
 		g.skat[1],
 	)
+}
+
+// Swap cards with the other defender in Revolution
+//
+// The defender gives the cards from their own hand to the other defender and
+// takes the same number of cards from the hand of the other defender in
+// return. Each defender exchanges once, possibly without any cards; play
+// starts once both of them are done.
+func (g *GameState) ExchangeCards(player int, give CardSet, take CardSet) error {
+	if g.phase != PhaseExchange {
+		return ErrWrongPhase
+	}
+	declarer := g.biddingState.Declarer()
+	if player < 0 || player >= len(g.players) || player == declarer {
+		return ErrNotYourTurn
+	}
+	if g.players[player].Exchanged {
+		return ErrAlreadyExchanged
+	}
+	if len(give) != len(take) {
+		return ErrInvalidExchange
+	}
+	partner := 3 - declarer - player
+	hand := g.players[player].Hand
+	partnerHand := g.players[partner].Hand
+	if len(give) > len(hand) || len(take) > len(partnerHand) {
+		return ErrInvalidExchange
+	}
+	var err error
+	for _, card := range give {
+		if hand, err = hand.Pop(card); err != nil {
+			return ErrInvalidExchange
+		}
+	}
+	for _, card := range take {
+		if partnerHand, err = partnerHand.Pop(card); err != nil {
+			return ErrInvalidExchange
+		}
+	}
+	g.players[player].Hand = append(hand, take...)
+	g.players[partner].Hand = append(partnerHand, give...)
+	g.players[player].Exchanged = true
+
+	if !g.players[partner].Exchanged {
+		return nil
+	}
+	g.startPlaying(GameTypeRevolution, g.skat)
 	return nil
 }
 
@@ -352,13 +815,42 @@ func (g *GameState) GetHand(player int) CardSet {
 	return g.players[player].Hand.Copy()
 }
 
+// Return the cards the player may play next
+//
+// Returns nil if the player cannot play a card right now.
+func (g *GameState) LegalCards(player int) CardSet {
+	if g.phase != PhasePlaying || g.claim != nil {
+		return nil
+	}
+	return g.playingState.LegalCards(player)
+}
+
 func (g *GameState) PlayCard(player int, card Card) error {
 	if g.phase != PhasePlaying {
 		return ErrWrongPhase
 	}
+	if g.claim != nil {
+		return ErrClaimPending
+	}
+	if g.dealing.Sealed() {
+		return ErrSealedDeal
+	}
+	return g.playCard(player, card)
+}
+
+func (g *GameState) playCard(player int, card Card) error {
 	if err := g.playingState.Play(player, card); err != nil {
 		return err
 	}
+	for i := range g.players {
+		g.players[i].PeekingLastTrick = false
+		g.players[i].PeekingSkat = false
+	}
+	if g.decidedEarly() {
+		// the outcome cannot change anymore, so the remaining cards go to
+		// the declarer and the game is scored right away
+		g.awardRemainingCards(g.playingState.Declarer())
+	}
 	err := g.EvaluateGame()
 	if err == ErrWrongPhase {
 		return nil
@@ -366,10 +858,305 @@ func (g *GameState) PlayCard(player int, card Card) error {
 	return err
 }
 
+// Test whether the declarer has already lost before all cards are played
+//
+// This is the case if the declarer of a Null game or any defender in a game
+// with Schwarz announced takes a trick.
+func (g *GameState) decidedEarly() bool {
+	switch g.playingState.GameType() {
+	case GameTypeNull, GameTypeRevolution:
+		return g.playingState.DeclarerTookTrick()
+	case GameTypeJunk:
+		return false
+	}
+	return g.modifiers.Test(GameModifierSchwarzAnnounced) && g.playingState.DefendersTookTrick()
+}
+
+// Take a look at cards which are not on the table anymore
+//
+// Players may look at the most recently completed trick. The declarer may
+// also look at the cards they pushed, unless they are playing Hand. The cards
+// are included in the blinded state of the player until the next card is
+// played.
+func (g *GameState) Peek(player int, target PeekTarget) error {
+	if g.phase != PhasePlaying {
+		return ErrWrongPhase
+	}
+	switch target {
+	case PeekLastTrick:
+		if _, winner := g.playingState.GetLastTrick(); winner == PlayerNone {
+			return ErrNothingToPeek
+		}
+		g.players[player].PeekingLastTrick = true
+	case PeekSkat:
+		if g.dealing.Sealed() {
+			return ErrSealedDeal
+		}
+		if player != g.playingState.Declarer() {
+			return ErrNotYourTurn
+		}
+		if len(g.pushed) == 0 {
+			return ErrNothingToPeek
+		}
+		g.players[player].PeekingSkat = true
+	default:
+		return ErrNothingToPeek
+	}
+	return nil
+}
+
+// Claim a number of the remaining tricks
+//
+// Claims are only possible between tricks and not in Junk games; in a Null
+// game, only the declarer may claim, and only to take no further trick. A
+// claim which holds however both sides play concedes nothing and is
+// rejected. If the claim holds against every possible defence, the claimed
+// tricks are awarded and the game is scored right away. Otherwise, the claim
+// is pending until the other side responds.
+func (g *GameState) Claim(player int, tricks int) error {
+	if g.phase != PhasePlaying {
+		return ErrWrongPhase
+	}
+	declarer := g.playingState.Declarer()
+	if declarer == PlayerNone {
+		return ErrInvalidGame
+	}
+	if g.claim != nil {
+		return ErrClaimPending
+	}
+	if g.dealing.Sealed() {
+		// nobody can verify a claim without knowing the hands
+		return ErrSealedDeal
+	}
+	if len(g.playingState.GetTable()) > 0 {
+		return ErrInvalidClaim
+	}
+	if tricks < 0 || tricks > g.playingState.RemainingTricks() {
+		return ErrInvalidClaim
+	}
+	if g.playingState.GameType().IsNull() {
+		if player != declarer || tricks != 0 {
+			return ErrInvalidClaim
+		}
+	} else {
+		trivial, err := g.playingState.claimAlwaysHolds(player, tricks)
+		switch err {
+		case nil:
+			if trivial {
+				return ErrInvalidClaim
+			}
+		case ErrClaimUndecided:
+			// the claim cannot be shown to concede nothing
+		default:
+			return err
+		}
+	}
+
+	g.claim = &Claim{
+		Player:   player,
+		Tricks:   tricks,
+		Accepted: [3]bool{},
+	}
+	holds, err := g.playingState.VerifyClaim(player, tricks)
+	switch err {
+	case nil:
+		if holds {
+			return g.resolveClaim()
+		}
+	case ErrClaimUndecided:
+		// the other side has to accept the claim
+	default:
+		g.claim = nil
+		return err
+	}
+	return nil
+}
+
+// Accept or reject the pending claim
+//
+// Only the players who play against the claiming player can respond. A
+// rejected claim is withdrawn and play continues.
+func (g *GameState) RespondToClaim(player int, accept bool) error {
+	if g.phase != PhasePlaying {
+		return ErrWrongPhase
+	}
+	if g.claim == nil {
+		return ErrNoClaim
+	}
+	declarer := g.playingState.Declarer()
+	claimingDeclarer := g.claim.Player == declarer
+	if (player == declarer) == claimingDeclarer {
+		return ErrNotYourTurn
+	}
+	if !accept {
+		g.claim = nil
+		return nil
+	}
+	g.claim.Accepted[player] = true
+	for i := range g.players {
+		if (i == declarer) != claimingDeclarer && !g.claim.Accepted[i] {
+			return nil
+		}
+	}
+	return g.resolveClaim()
+}
+
+func (g *GameState) resolveClaim() error {
+	claim := g.claim
+	g.claim = nil
+	if err := g.playingState.PlayOutClaim(claim.Player, claim.Tricks); err != nil {
+		return err
+	}
+	return g.EvaluateGame()
+}
+
+// Give up the game
+//
+// The declarer may resign during declaration or play, in which case the game
+// is lost and the remaining cards go to the defenders; Schneider is only
+// counted if the defenders had already reached it. The defenders may
+// concede the remaining tricks to the declarer; this takes effect once both
+// of them have resigned.
+func (g *GameState) Resign(player int) error {
+	if g.phase != PhaseDeclaration && g.phase != PhasePlaying {
+		return ErrWrongPhase
+	}
+	declarer := g.Declarer()
+	if declarer == PlayerNone {
+		return ErrInvalidGame
+	}
+	if player != declarer && g.phase != PhasePlaying {
+		return ErrWrongPhase
+	}
+	if g.players[player].Resigned {
+		return ErrAlreadyResigned
+	}
+	g.players[player].Resigned = true
+
+	if player == declarer {
+		if g.phase == PhaseDeclaration {
+			if d := g.SealedDeal(); d != nil {
+				return g.startAudit(d)
+			}
+			g.evaluateResignation()
+			return nil
+		}
+		// it does not matter which defender gets the cards, as only their
+		// sum is counted
+		g.awardRemainingCards((declarer + 1) % 3)
+		return g.EvaluateGame()
+	}
+
+	if !g.defendersConceded() {
+		return nil
+	}
+	if g.GameType().IsNull() {
+		// in a Null game, the declarer wins by taking no tricks at all
+		g.awardRemainingCards((declarer + 1) % 3)
+	} else {
+		g.awardRemainingCards(declarer)
+	}
+	return g.EvaluateGame()
+}
+
+// Test whether both defenders have resigned
+func (g *GameState) defendersConceded() bool {
+	declarer := g.Declarer()
+	for i := range g.players {
+		if i != declarer && !g.players[i].Resigned {
+			return false
+		}
+	}
+	return true
+}
+
+// Double the game as a defender
+//
+// Kontra may be given by either defender as long as they have not played
+// their first card, unless the house rules disable Kontra and Re.
+func (g *GameState) Kontra(player int) error {
+	if g.phase != PhasePlaying {
+		return ErrWrongPhase
+	}
+	declarer := g.playingState.Declarer()
+	if declarer == PlayerNone {
+		return ErrInvalidGame
+	}
+	if !g.rules.KontraRe {
+		return ErrRuleDisabled
+	}
+	if player == declarer {
+		return ErrNotYourTurn
+	}
+	if g.modifiers.Test(GameModifierKontra) {
+		return ErrAlreadyDoubled
+	}
+	if g.handSize(player) < 10 {
+		return ErrTooLateToDouble
+	}
+	g.modifiers = g.modifiers.With(GameModifierKontra)
+	g.kontraPlayer = player
+	g.kontraDeclarerCards = g.handSize(declarer)
+	return nil
+}
+
+// Redouble the game as the declarer after a Kontra
+//
+// Re may be given until the declarer plays their next card after the Kontra.
+func (g *GameState) Re(player int) error {
+	if g.phase != PhasePlaying {
+		return ErrWrongPhase
+	}
+	declarer := g.playingState.Declarer()
+	if declarer == PlayerNone {
+		return ErrInvalidGame
+	}
+	if player != declarer {
+		return ErrNotYourTurn
+	}
+	if !g.modifiers.Test(GameModifierKontra) {
+		return ErrNoKontra
+	}
+	if g.modifiers.Test(GameModifierRe) {
+		return ErrAlreadyDoubled
+	}
+	if g.handSize(declarer) != g.kontraDeclarerCards {
+		return ErrTooLateToDouble
+	}
+	g.modifiers = g.modifiers.With(GameModifierRe)
+	return nil
+}
+
 func (g *GameState) GetSkat() CardSet {
 	return g.skat.Copy()
 }
 
+// Return the full deal and the pushed cards of a finished game
+//
+// Returns nil before the game has been scored or passed in. A sealed deal
+// which was passed in is never opened.
+func (g *GameState) Reveal() *GameReveal {
+	if g.phase != PhaseScored && g.phase != PhasePassedIn {
+		return nil
+	}
+	if g.phase == PhasePassedIn && g.SealedDeal() != nil {
+		return nil
+	}
+	result := &GameReveal{
+		Hands: make([]CardSet, len(g.players)),
+		Skat:  g.dealtSkat.Copy(),
+	}
+	for i := range g.players {
+		result.Hands[i] = g.players[i].DealtHand.Copy()
+	}
+	if len(g.pushed) > 0 {
+		result.Pushed = g.pushed.Copy()
+	} else if g.JunkPushes() > 0 {
+		result.Pushed = g.skat.Copy()
+	}
+	return result
+}
+
 func (g *GameState) Modifiers() GameModifier {
 	return g.modifiers
 }
@@ -378,6 +1165,39 @@ func (g *GameState) Playing() *PlayingState {
 	return g.playingState
 }
 
+// Return the declarer
+//
+// Returns PlayerNone before the bidding has concluded and in Junk games.
+func (g *GameState) Declarer() int {
+	if g.biddingState == nil {
+		return PlayerNone
+	}
+	return g.biddingState.Declarer()
+}
+
+// Return the type of the game being played
+//
+// Returns InvalidGameType before the game has been declared.
+func (g *GameState) GameType() GameType {
+	if g.phase == PhaseExchange {
+		return GameTypeRevolution
+	}
+	if g.playingState == nil {
+		return InvalidGameType
+	}
+	return g.playingState.GameType()
+}
+
+// Return the hands and the skat as they were dealt
+//
+// Returns nil sets before the cards have been dealt.
+func (g *GameState) Dealt() (hands [3]CardSet, skat CardSet) {
+	for i := range g.players {
+		hands[i] = g.players[i].DealtHand.Copy()
+	}
+	return hands, g.dealtSkat.Copy()
+}
+
 func (g *GameState) GetScore(player int) int {
 	return g.players[player].Score
 }
@@ -386,17 +1206,37 @@ func (g *GameState) GetLossReason() string {
 	return g.lossReason
 }
 
+func (g *GameState) GetGameValue() int {
+	return g.finalGameValue
+}
+
+// Transition PhasePlaying -> PhaseScored
+//
+// With a sealed deal, the game goes to PhaseAudit instead and is scored once
+// all keys have been revealed.
 func (g *GameState) EvaluateGame() error {
 	if g.phase != PhasePlaying {
 		return ErrWrongPhase
 	}
+	if d := g.SealedDeal(); d != nil {
+		return g.startAudit(d)
+	}
 	if len(g.playingState.GetHand(PlayerInitialForehand)) > 0 || len(g.playingState.GetHand(PlayerInitialMiddlehand)) > 0 || len(g.playingState.GetHand(PlayerInitialRearhand)) > 0 {
 		return ErrWrongPhase
 	}
-	declarer := g.biddingState.Declarer()
+	g.scoreGame()
+	return nil
+}
+
+func (g *GameState) scoreGame() {
 	for i := range g.players {
 		g.players[i].WonCards = g.playingState.GetWonCards(i)
 	}
+	if g.playingState.GameType() == GameTypeJunk {
+		g.evaluateJunk()
+		return
+	}
+	declarer := g.biddingState.Declarer()
 	resultModifiers, declarerCardPoints, _ := EvaluateWonCards(
 		[3]CardSet{
 			g.players[0].WonCards,
@@ -409,8 +1249,11 @@ func (g *GameState) EvaluateGame() error {
 	g.jackStrength = declarerHand.GetMatadorsJackStrength(
 		g.playingState.GameType(),
 	)
+	if g.players[declarer].Resigned {
+		resultModifiers = g.resignedModifiers()
+	}
 	modifiers := g.modifiers | resultModifiers
-	baseValue, factor := CalculateGameValue(
+	baseValue, factor := g.rules.GameValue(
 		declarerHand,
 		g.playingState.GameType(),
 		modifiers,
@@ -423,6 +1266,16 @@ func (g *GameState) EvaluateGame() error {
 		g.playingState.GameType(),
 		modifiers,
 	)
+	if g.players[declarer].Resigned {
+		declarerWon = false
+		lossReason = LossReasonResigned
+	} else if g.defendersConceded() && lossReason != LossReasonOverbid {
+		// the cards alone do not always show it, e.g. the skat counts for
+		// the declarer in a Null game
+		declarerWon = true
+		lossReason = ""
+	}
+	gameValue = gameValue * g.multiplier
 	g.finalGameValue = gameValue
 	playerScores := g.scoring.CalculateScore(
 		gameValue,
@@ -435,29 +1288,160 @@ func (g *GameState) EvaluateGame() error {
 	}
 	g.lossReason = lossReason
 	g.phase = PhaseScored
-	return nil
+}
+
+// Return the result modifiers of a game the declarer gave up during play
+//
+// Only the tricks taken before the declarer resigned count, not the remaining
+// cards awarded to the defenders: the declarer is Schneider if the defenders
+// had taken 90 card points. Schwarz cannot be reached while cards are left.
+func (g *GameState) resignedModifiers() GameModifier {
+	declarer := g.biddingState.Declarer()
+	defenderScore := 0
+	for _, trick := range g.playingState.GetTrickHistory() {
+		if trick.Winner != declarer {
+			defenderScore = defenderScore + trick.Cards.Value()
+		}
+	}
+	if defenderScore >= 90 {
+		return GameModifierSchneider
+	}
+	return NoGameModifiers
+}
+
+// Score a game the declarer gave up before declaring it
+//
+// Without a declared game, the called bid is lost.
+func (g *GameState) evaluateResignation() {
+	declarer := g.biddingState.Declarer()
+	gameValue := g.biddingState.CalledGameValue() * g.multiplier
+	g.finalGameValue = gameValue
+	playerScores := g.scoring.CalculateScore(
+		gameValue,
+		declarer,
+		false,
+	)
+	for i := range g.players {
+		g.players[i].Score = playerScores[i]
+	}
+	g.lossReason = LossReasonResigned
+	g.phase = PhaseScored
+}
+
+func (g *GameState) evaluateJunk() {
+	playerScores, gameValue, modifiers := EvaluateJunkGame(
+		[3]CardSet{
+			g.players[0].WonCards,
+			g.players[1].WonCards,
+			g.players[2].WonCards,
+		},
+		[3]int{
+			g.playingState.GetTrickCount(0),
+			g.playingState.GetTrickCount(1),
+			g.playingState.GetTrickCount(2),
+		},
+	)
+	// each push in Schieberamsch doubles the result
+	factor := g.multiplier << uint(g.JunkPushes())
+	g.finalGameValue = gameValue * factor
+	g.modifiers = modifiers
+	for i := range g.players {
+		g.players[i].Score = playerScores[i] * factor
+	}
+	g.phase = PhaseScored
+}
+
+// Return the hands which are visible to all players
+//
+// Returns nil if all hands are hidden.
+func (g *GameState) openHands() []CardSet {
+	if !g.modifiers.Test(GameModifierOuvert) {
+		return nil
+	}
+	declarer := g.Declarer()
+	if declarer == PlayerNone {
+		return nil
+	}
+	result := make([]CardSet, len(g.players))
+	result[declarer] = g.GetHand(declarer)
+	return result
 }
 
 func (g *GameState) BlindedForPlayer(player int) (result *BlindedGameState) {
+	result = g.blinded()
+	result.Hand = g.GetHand(player)
+	if g.phase == PhasePlaying {
+		if g.players[player].PeekingLastTrick {
+			trick, winner := g.playingState.GetLastTrick()
+			result.LastTrick = CardSet{trick[0], trick[1], trick[2]}
+			result.LastTrickWinner = winner
+		}
+		if g.players[player].PeekingSkat {
+			result.PushedCards = g.pushed.Copy()
+		}
+		result.LegalCards = g.LegalCards(player)
+	}
+	if g.phase == PhaseExchange && player != g.Declarer() {
+		result.PartnerHand = g.players[3-g.Declarer()-player].Hand.Copy()
+	}
+	return result
+}
+
+// Return the state as seen by the dealer who sits out this game
+//
+// The hand is the one of the player the dealer is watching, if any.
+func (g *GameState) BlindedForDealer() (result *BlindedGameState) {
+	result = g.blinded()
+	if g.dealerLookingAtHand != PlayerNone && g.phase != PhaseScored {
+		result.Hand = g.GetHand(g.dealerLookingAtHand)
+	}
+	return result
+}
+
+func (g *GameState) blinded() (result *BlindedGameState) {
 	players := make([]BlindedPlayerState, 3)
 	for i := range players {
-		players[i].Ncards = len(g.GetHand(i))
+		players[i].Ncards = g.handSize(i)
+		players[i].SeedCommitted = g.players[i].SeedCommitment != nil
 		players[i].SeedProvided = g.players[i].Seed != nil
+		players[i].Resigned = g.players[i].Resigned
+		players[i].Exchanged = g.players[i].Exchanged
 	}
 
 	skatCards := 2
-	if g.phase == PhaseDeclaration || g.phase == PhaseScored || g.phase == PhasePlaying {
+	if g.phase == PhaseDeclaration || g.phase == PhaseScored || g.phase == PhasePlaying || g.phase == PhaseExchange || g.phase == PhaseAudit {
 		if !g.modifiers.Test(GameModifierHand) {
 			skatCards = 0
 		}
 	}
+	if g.phase == PhasePlaying && g.playingState.GameType() == GameTypeJunk {
+		skatCards = 2
+	}
+	if g.phase == PhaseJunkPushing && g.skatTaken {
+		skatCards = 0
+	}
 
 	result = &BlindedGameState{
-		Phase:      g.phase,
-		Players:    players,
-		Hand:       g.GetHand(player),
-		SkatCards:  skatCards,
-		ServerSeed: g.serverSeed,
+		Phase:            g.phase,
+		Players:          players,
+		SkatCards:        skatCards,
+		ServerCommitment: g.serverCommitment,
+		SeedsCommitted:   g.SeedsCommitted(),
+		KontraPlayer:     g.kontraPlayer,
+		Multiplier:       g.multiplier,
+		LastTrickWinner:  PlayerNone,
+		WithDealer:       g.withDealer,
+		WatchedPlayer:    g.dealerLookingAtHand,
+		Rules:            g.Rules(),
+	}
+	if g.withDealer {
+		result.DealerSeedCommitted = g.dealerCommitment != nil
+		result.DealerSeedProvided = g.dealerSeed != nil
+	}
+	// the server reveals its seed once it cannot influence the other seeds
+	// anymore
+	if g.phase != PhaseInit || result.SeedsCommitted {
+		result.ServerSeed = g.serverSeed
 	}
 
 	if g.phase == PhaseBidding {
@@ -466,37 +1450,80 @@ func (g *GameState) BlindedForPlayer(player int) (result *BlindedGameState) {
 			Caller:           g.biddingState.Caller(),
 			Responder:        g.biddingState.Responder(),
 			AwaitingResponse: g.biddingState.AwaitingResponse(),
+			NextBid:          g.biddingState.NextLegalBid(),
 		}
 	}
 
-	if g.phase == PhaseDeclaration || g.phase == PhasePlaying || g.phase == PhaseScored {
+	if g.phase == PhaseDeclaration || g.phase == PhasePlaying || g.phase == PhaseScored || g.phase == PhasePassedIn || g.phase == PhaseJunkPushing || g.phase == PhaseExchange || g.phase == PhaseAudit {
 		result.Declarer = g.biddingState.Declarer()
 		result.LastBiddingCall = g.biddingState.LastBid()
 	}
 
-	if g.phase == PhasePlaying || g.phase == PhaseScored {
-		result.AnnouncedModifiers = g.modifiers & (AnnouncementModifiers | GameModifierHand)
+	if g.phase == PhasePlaying || g.phase == PhaseScored || g.phase == PhaseAudit {
+		result.AnnouncedModifiers = g.modifiers & (AnnouncementModifiers | DoublingModifiers | GameModifierHand)
+	}
+
+	if g.skatPasses != nil {
+		result.SkatPasses = make([]SkatPass, len(g.skatPasses))
+		copy(result.SkatPasses, g.skatPasses)
+	}
+
+	if g.phase == PhaseJunkPushing {
+		result.CurrentPlayer = g.JunkPushingPlayer()
+		result.GameType = GameTypeJunk
+	}
+
+	if g.phase == PhaseExchange {
+		result.CurrentPlayer = PlayerNone
+		result.GameType = GameTypeRevolution
+		result.AnnouncedModifiers = g.modifiers
+		result.OpenHands = g.openHands()
 	}
 
 	if g.phase == PhasePlaying {
+		result.CurrentForehand = g.playingState.GetForehand()
 		result.CurrentPlayer = g.playingState.GetCurrentPlayer()
 		result.Table = g.playingState.GetTable()
 		result.GameType = g.playingState.GameType()
 		result.AnnouncedModifiers = g.modifiers
+		result.OpenHands = g.openHands()
+		if g.claim != nil {
+			claim := *g.claim
+			result.Claim = &claim
+		}
+	}
+
+	if g.phase == PhaseAudit {
+		result.GameType = g.GameType()
+	}
+
+	if d := g.SealedDeal(); d != nil {
+		result.SealedDeal = d.copy(false)
 	}
 
 	if g.phase == PhaseScored {
+		result.GameType = g.GameType()
+		if g.playingState != nil {
+			result.Tricks = g.playingState.GetTrickHistory()
+		}
 		result.LossReason = g.lossReason
 		result.FinalModifiers = g.modifiers
 		for i := range result.Players {
 			result.Players[i].WonCardPoints = g.players[i].WonCards.Value()
 			result.Players[i].AwardedScore = g.players[i].Score
-			result.Players[i].Seed = g.players[i].Seed
 		}
-		result.DealerSeed = g.dealerSeed
 		result.FinalGameValue = g.finalGameValue
 		result.JackStrength = g.jackStrength
 	}
 
+	// the deal of a game which was passed in can be checked as well
+	if g.phase == PhaseScored || g.phase == PhasePassedIn {
+		for i := range result.Players {
+			result.Players[i].Seed = g.players[i].Seed
+		}
+		result.DealerSeed = g.dealerSeed
+		result.Reveal = g.Reveal()
+	}
+
 	return result
 }
@@ -7,31 +7,70 @@ import (
 )
 
 func testGetInitPhaseGame(t *testing.T) *GameState {
-	g, err := NewGame(false, LeagueScoreDefinition())
+	g, err := NewGame(false, LeagueScoreDefinition(), StandardRuleSet())
 	assert.Nil(t, err)
 	assert.Equal(t, PhaseInit, g.Phase())
 	return g
 }
 
-func testGetBiddingPhaseGame(t *testing.T) *GameState {
-	g := testGetInitPhaseGame(t)
+// Commit all players to empty seeds
+func testCommitSeeds(t *testing.T, g *GameState) {
+	for i := 0; i < 3; i = i + 1 {
+		assert.Nil(t, g.CommitSeed(i, CommitToSeed([]byte{})))
+	}
+}
+
+// Deal the game from empty seeds
+func testDeal(t *testing.T, g *GameState) {
 	assert.Nil(t, g.ForceServerSeed([]byte{}))
-	assert.Nil(t, g.SetSeed(PlayerInitialForehand, []byte{}))
-	assert.Nil(t, g.SetSeed(PlayerInitialMiddlehand, []byte{}))
-	assert.Nil(t, g.SetSeed(PlayerInitialRearhand, []byte{}))
-	assert.Equal(t, PhaseBidding, g.Phase())
+	testCommitSeeds(t, g)
+	for i := 0; i < 3; i = i + 1 {
+		assert.Nil(t, g.SetSeed(i, []byte{}))
+	}
+}
+
+// Create a game with the given rules and deal it from empty seeds
+func testNewDealtGame(t *testing.T, rules *RuleSet) *GameState {
+	g, err := NewGame(false, LeagueScoreDefinition(), rules)
+	assert.Nil(t, err)
+	testDeal(t, g)
 	return g
 }
 
-func testGetDeclarationPhaseGame(t *testing.T) *GameState {
-	g := testGetBiddingPhaseGame(t)
+// Let middlehand win the bidding at 18
+func testWinBidding(t *testing.T, g *GameState) {
 	assert.Nil(t, g.CallBid(PlayerInitialMiddlehand, 18))
 	assert.Nil(t, g.RespondToBid(PlayerInitialForehand, false))
 	assert.Nil(t, g.CallBid(PlayerInitialRearhand, BidPass))
+}
+
+// Let all players pass
+func testPassBidding(t *testing.T, g *GameState) {
+	assert.Nil(t, g.CallBid(PlayerInitialMiddlehand, BidPass))
+	assert.Nil(t, g.CallBid(PlayerInitialRearhand, BidPass))
+	assert.Nil(t, g.CallBid(PlayerInitialForehand, BidPass))
+}
+
+func testGetBiddingPhaseGame(t *testing.T) *GameState {
+	g := testNewDealtGame(t, StandardRuleSet())
+	assert.Equal(t, PhaseBidding, g.Phase())
+	return g
+}
+
+func testGetDeclarationPhaseGame(t *testing.T) *GameState {
+	g := testGetBiddingPhaseGame(t)
+	testWinBidding(t, g)
 	assert.Equal(t, PhaseDeclaration, g.Phase())
 	return g
 }
 
+func testGetJunkPhaseGame(t *testing.T) *GameState {
+	g := testGetBiddingPhaseGame(t)
+	testPassBidding(t, g)
+	assert.Equal(t, PhasePlaying, g.Phase())
+	return g
+}
+
 func testGetPlayingPhaseGame(t *testing.T, gameType GameType) *GameState {
 	g := testGetDeclarationPhaseGame(t)
 	assert.Nil(t, g.Declare(PlayerInitialMiddlehand, gameType, NoGameModifiers, nil))
@@ -40,34 +79,14 @@ func testGetPlayingPhaseGame(t *testing.T, gameType GameType) *GameState {
 
 func testGetDonePlayingPhaseGame(t *testing.T, gameType GameType) *GameState {
 	g := testGetPlayingPhaseGame(t, gameType)
-	play := g.Playing()
-	for i := 0; i < 10; i = i + 1 {
-		// 10 tricks
-		player := play.GetCurrentPlayer()
-		assert.Nil(t, play.Play(player, play.GetHand(player)[0]))
-		for j := 0; j < 2; j = j + 1 {
-			// 2 non-forehand players
-			player := play.GetCurrentPlayer()
-			hand := play.GetHand(player)
-			success := false
-			for _, card := range hand {
-				if play.Play(player, card) == nil {
-					success = true
-					break
-				}
-			}
-			assert.True(t, success)
-		}
-	}
-	assert.Equal(t, 0, len(g.GetHand(PlayerInitialForehand)))
-	assert.Equal(t, 0, len(g.GetHand(PlayerInitialMiddlehand)))
-	assert.Equal(t, 0, len(g.GetHand(PlayerInitialRearhand)))
+	testPlayOut(t, g.Playing(), g.Playing().Play, -1)
 	return g
 }
 
 func TestGameStateInitialPhase(t *testing.T) {
 	t.Run("rejects dealing without all seeds", func(t *testing.T) {
 		g := testGetInitPhaseGame(t)
+		testCommitSeeds(t, g)
 
 		var err error
 
@@ -93,6 +112,73 @@ func TestGameStateInitialPhase(t *testing.T) {
 	})
 }
 
+func TestGameStateSeedCommitment(t *testing.T) {
+	t.Run("rejects dealing without commitments", func(t *testing.T) {
+		g := testGetInitPhaseGame(t)
+		assert.Equal(t, ErrMissingCommitment, g.Deal())
+	})
+
+	t.Run("rejects reveals before all commitments", func(t *testing.T) {
+		g := testGetInitPhaseGame(t)
+		assert.Nil(t, g.CommitSeed(PlayerInitialForehand, CommitToSeed([]byte{})))
+		assert.Nil(t, g.CommitSeed(PlayerInitialMiddlehand, CommitToSeed([]byte{})))
+		assert.False(t, g.SeedsCommitted())
+		assert.Equal(t, ErrMissingCommitment, g.SetSeed(PlayerInitialForehand, []byte{}))
+		assert.False(t, g.BlindedForPlayer(PlayerInitialForehand).Players[PlayerInitialForehand].SeedProvided)
+	})
+
+	t.Run("rejects invalid and repeated commitments", func(t *testing.T) {
+		g := testGetInitPhaseGame(t)
+		assert.Equal(t, ErrInvalidCommitment, g.CommitSeed(PlayerInitialForehand, []byte{1, 2, 3}))
+		assert.Nil(t, g.CommitSeed(PlayerInitialForehand, CommitToSeed([]byte{})))
+		assert.Equal(t, ErrSeedCommitted, g.CommitSeed(PlayerInitialForehand, CommitToSeed([]byte{1})))
+	})
+
+	t.Run("rejects seeds which do not match the commitment", func(t *testing.T) {
+		g := testGetInitPhaseGame(t)
+		testCommitSeeds(t, g)
+		assert.Equal(t, ErrSeedMismatch, g.SetSeed(PlayerInitialForehand, []byte{1}))
+		assert.False(t, g.BlindedForPlayer(PlayerInitialForehand).Players[PlayerInitialForehand].SeedProvided)
+		assert.Nil(t, g.SetSeed(PlayerInitialForehand, []byte{}))
+	})
+
+	t.Run("server seed is revealed after all commitments", func(t *testing.T) {
+		g := testGetInitPhaseGame(t)
+		blinded := g.BlindedForPlayer(PlayerInitialForehand)
+		assert.Nil(t, blinded.ServerSeed)
+		assert.Equal(t, CommitToSeed(g.ServerSeed()), blinded.ServerCommitment)
+
+		testCommitSeeds(t, g)
+		blinded = g.BlindedForPlayer(PlayerInitialForehand)
+		assert.True(t, blinded.SeedsCommitted)
+		assert.True(t, blinded.Players[PlayerInitialRearhand].SeedCommitted)
+		assert.True(t, VerifySeedCommitment(blinded.ServerSeed, blinded.ServerCommitment))
+	})
+
+	t.Run("server seed cannot be replaced after a commitment", func(t *testing.T) {
+		g := testGetInitPhaseGame(t)
+		assert.Nil(t, g.CommitSeed(PlayerInitialRearhand, CommitToSeed([]byte{})))
+		assert.Equal(t, ErrSeedCommitted, g.ForceServerSeed([]byte{}))
+	})
+
+	t.Run("dealer has to commit as well", func(t *testing.T) {
+		g, err := NewGame(true, LeagueScoreDefinition(), StandardRuleSet())
+		assert.Nil(t, err)
+		testCommitSeeds(t, g)
+		assert.False(t, g.SeedsCommitted())
+		assert.Equal(t, ErrMissingCommitment, g.SetSeed(PlayerInitialForehand, []byte{}))
+		assert.Nil(t, g.CommitDealerSeed(CommitToSeed([]byte{1})))
+		assert.True(t, g.BlindedForDealer().DealerSeedCommitted)
+		assert.Equal(t, ErrSeedCommitted, g.CommitDealerSeed(CommitToSeed([]byte{1})))
+		assert.Equal(t, ErrSeedMismatch, g.SetDealerSeed([]byte{2}))
+	})
+
+	t.Run("rejects dealer commitment without dealer", func(t *testing.T) {
+		g := testGetInitPhaseGame(t)
+		assert.Equal(t, ErrNoDealer, g.CommitDealerSeed(CommitToSeed([]byte{})))
+	})
+}
+
 func TestGameStateBiddingPhase(t *testing.T) {
 	t.Run("hands are dealt", func(t *testing.T) {
 		g := testGetBiddingPhaseGame(t)
@@ -337,3 +423,864 @@ func TestGameStatePlayingPhaseCompleted(t *testing.T) {
 		assert.Equal(t, LossReasonNotNull, g.GetLossReason())
 	})
 }
+
+func TestGameStateJunk(t *testing.T) {
+	t.Run("all players passing starts a junk game", func(t *testing.T) {
+		g := testGetJunkPhaseGame(t)
+		assert.Equal(t, GameTypeJunk, g.Playing().GameType())
+		assert.Equal(t, PlayerNone, g.Playing().Declarer())
+		assert.Equal(t, PlayerInitialForehand, g.Playing().GetCurrentPlayer())
+		assert.False(t, g.Modifiers().Test(GameModifierHand))
+	})
+
+	t.Run("reject declaration", func(t *testing.T) {
+		g := testGetJunkPhaseGame(t)
+		assert.Equal(t, ErrWrongPhase, g.TakeSkat(PlayerInitialForehand))
+		assert.Equal(t, ErrWrongPhase, g.Declare(PlayerInitialForehand, GameTypeGrand, NoGameModifiers, nil))
+	})
+
+	t.Run("skat is hidden during play", func(t *testing.T) {
+		g := testGetJunkPhaseGame(t)
+		blinded := g.BlindedForPlayer(PlayerInitialForehand)
+		assert.Equal(t, 2, blinded.SkatCards)
+		assert.Equal(t, PlayerNone, blinded.Declarer)
+	})
+
+	t.Run("game is scored after the last trick", func(t *testing.T) {
+		g := testGetJunkPhaseGame(t)
+		testPlayOut(t, g.Playing(), g.PlayCard, -1)
+		assert.Equal(t, PhaseScored, g.Phase())
+
+		blinded := g.BlindedForPlayer(PlayerInitialForehand)
+		assert.Equal(t, GameTypeJunk, blinded.GameType)
+		totalPoints := 0
+		highest := 0
+		for _, player := range blinded.Players {
+			totalPoints = totalPoints + player.WonCardPoints
+			if player.WonCardPoints > highest {
+				highest = player.WonCardPoints
+			}
+		}
+		assert.Equal(t, 120, totalPoints)
+		for i, player := range blinded.Players {
+			if player.WonCardPoints == highest && !g.Modifiers().Test(GameModifierDurchmarsch) {
+				assert.Equal(t, -blinded.FinalGameValue, g.GetScore(i))
+			} else if !g.Modifiers().Test(GameModifierDurchmarsch) {
+				assert.Equal(t, 0, g.GetScore(i))
+			}
+		}
+	})
+}
+
+func TestGameStateKontraRe(t *testing.T) {
+	t.Run("reject kontra outside of playing phase", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		assert.Equal(t, ErrWrongPhase, g.Kontra(PlayerInitialForehand))
+	})
+
+	t.Run("reject kontra from declarer", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeHearts)
+		assert.Equal(t, ErrNotYourTurn, g.Kontra(PlayerInitialMiddlehand))
+	})
+
+	t.Run("reject kontra in junk game", func(t *testing.T) {
+		g := testGetJunkPhaseGame(t)
+		assert.Equal(t, ErrInvalidGame, g.Kontra(PlayerInitialForehand))
+	})
+
+	t.Run("defender can give kontra before their first card", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeHearts)
+		assert.Nil(t, g.Kontra(PlayerInitialRearhand))
+		assert.True(t, g.Modifiers().Test(GameModifierKontra))
+		blinded := g.BlindedForPlayer(PlayerInitialForehand)
+		assert.True(t, blinded.AnnouncedModifiers.Test(GameModifierKontra))
+		assert.Equal(t, PlayerInitialRearhand, blinded.KontraPlayer)
+	})
+
+	t.Run("reject kontra after first card of the defender", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeHearts)
+		hand := g.GetHand(PlayerInitialForehand)
+		assert.Nil(t, g.PlayCard(PlayerInitialForehand, hand[0]))
+		assert.Equal(t, ErrTooLateToDouble, g.Kontra(PlayerInitialForehand))
+		assert.Nil(t, g.Kontra(PlayerInitialRearhand))
+	})
+
+	t.Run("reject second kontra", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeHearts)
+		assert.Nil(t, g.Kontra(PlayerInitialRearhand))
+		assert.Equal(t, ErrAlreadyDoubled, g.Kontra(PlayerInitialForehand))
+	})
+
+	t.Run("reject re without kontra", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeHearts)
+		assert.Equal(t, ErrNoKontra, g.Re(PlayerInitialMiddlehand))
+	})
+
+	t.Run("reject re from defender", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeHearts)
+		assert.Nil(t, g.Kontra(PlayerInitialRearhand))
+		assert.Equal(t, ErrNotYourTurn, g.Re(PlayerInitialForehand))
+	})
+
+	t.Run("declarer can give re after kontra", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeHearts)
+		assert.Nil(t, g.Kontra(PlayerInitialRearhand))
+		assert.Nil(t, g.Re(PlayerInitialMiddlehand))
+		assert.True(t, g.Modifiers().Test(GameModifierRe))
+		assert.Equal(t, ErrAlreadyDoubled, g.Re(PlayerInitialMiddlehand))
+	})
+
+	t.Run("reject re after the declarer played a card", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeHearts)
+		assert.Nil(t, g.Kontra(PlayerInitialRearhand))
+		play := g.Playing()
+		for i := 0; i < 2; i = i + 1 {
+			player := play.GetCurrentPlayer()
+			for _, card := range play.GetHand(player) {
+				if g.PlayCard(player, card) == nil {
+					break
+				}
+			}
+		}
+		assert.Equal(t, 9, len(g.GetHand(PlayerInitialMiddlehand)))
+		assert.Equal(t, ErrTooLateToDouble, g.Re(PlayerInitialMiddlehand))
+	})
+
+	t.Run("kontra and re double the score", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeDiamonds)
+		assert.Nil(t, g.Kontra(PlayerInitialForehand))
+		testPlayOut(t, g.Playing(), g.Playing().Play, -1)
+		assert.Nil(t, g.EvaluateGame())
+		assert.Equal(t, -108, g.GetScore(PlayerInitialMiddlehand))
+
+		g = testGetPlayingPhaseGame(t, GameTypeDiamonds)
+		assert.Nil(t, g.Kontra(PlayerInitialForehand))
+		assert.Nil(t, g.Re(PlayerInitialMiddlehand))
+		testPlayOut(t, g.Playing(), g.Playing().Play, -1)
+		assert.Nil(t, g.EvaluateGame())
+		assert.Equal(t, -216, g.GetScore(PlayerInitialMiddlehand))
+		assert.Equal(t, GameModifierKontra|GameModifierRe, g.BlindedForPlayer(PlayerInitialForehand).FinalModifiers&DoublingModifiers)
+	})
+}
+
+func TestGameStateOuvert(t *testing.T) {
+	t.Run("announced modifiers are kept", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		assert.Nil(t, g.Declare(PlayerInitialMiddlehand, GameTypeHearts, GameModifierSchneiderAnnounced, nil))
+		assert.True(t, g.Modifiers().Test(GameModifierSchneiderAnnounced))
+		assert.True(t, g.BlindedForPlayer(PlayerInitialForehand).AnnouncedModifiers.Test(GameModifierSchneiderAnnounced))
+	})
+
+	t.Run("ouvert implies schneider and schwarz announced", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		assert.Nil(t, g.Declare(PlayerInitialMiddlehand, GameTypeGrand, GameModifierOuvert, nil))
+		assert.True(t, g.Modifiers().Test(GameModifierSchneiderAnnounced))
+		assert.True(t, g.Modifiers().Test(GameModifierSchwarzAnnounced))
+	})
+
+	t.Run("reject suit ouvert without hand", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		skat := g.GetSkat()
+		assert.Nil(t, g.TakeSkat(PlayerInitialMiddlehand))
+		assert.Equal(t, ErrInvalidGame, g.Declare(PlayerInitialMiddlehand, GameTypeGrand, GameModifierOuvert, skat))
+	})
+
+	t.Run("null ouvert does not imply schwarz", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		skat := g.GetSkat()
+		assert.Nil(t, g.TakeSkat(PlayerInitialMiddlehand))
+		assert.Nil(t, g.Declare(PlayerInitialMiddlehand, GameTypeNull, GameModifierOuvert, skat))
+		assert.False(t, g.Modifiers().Test(GameModifierSchwarzAnnounced))
+	})
+
+	t.Run("declarer hand is hidden without ouvert", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeNull)
+		assert.Nil(t, g.BlindedForPlayer(PlayerInitialForehand).OpenHands)
+	})
+
+	t.Run("declarer hand is open to everyone", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		assert.Nil(t, g.Declare(PlayerInitialMiddlehand, GameTypeNull, GameModifierOuvert, nil))
+		for i := 0; i < 3; i = i + 1 {
+			blinded := g.BlindedForPlayer(i)
+			assert.Equal(t, 3, len(blinded.OpenHands))
+			assert.Nil(t, blinded.OpenHands[PlayerInitialForehand])
+			assert.Nil(t, blinded.OpenHands[PlayerInitialRearhand])
+			assert.Equal(t, g.GetHand(PlayerInitialMiddlehand), blinded.OpenHands[PlayerInitialMiddlehand])
+		}
+	})
+
+	t.Run("open hand follows played cards", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		assert.Nil(t, g.Declare(PlayerInitialMiddlehand, GameTypeNull, GameModifierOuvert, nil))
+		play := g.Playing()
+		for len(g.GetHand(PlayerInitialMiddlehand)) == 10 {
+			player := play.GetCurrentPlayer()
+			for _, card := range play.GetHand(player) {
+				if g.PlayCard(player, card) == nil {
+					break
+				}
+			}
+		}
+		blinded := g.BlindedForPlayer(PlayerInitialForehand)
+		assert.Equal(t, 9, len(blinded.OpenHands[PlayerInitialMiddlehand]))
+		assert.Equal(t, g.GetHand(PlayerInitialMiddlehand), blinded.OpenHands[PlayerInitialMiddlehand])
+	})
+}
+
+func TestGameStateEarlyEnd(t *testing.T) {
+	t.Run("null game ends with the first trick of the declarer", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeNull)
+		played := testPlayOut(t, g.Playing(), g.PlayCard, -1)
+		assert.Equal(t, PhaseScored, g.Phase())
+		assert.Equal(t, LossReasonNotNull, g.GetLossReason())
+		assert.Equal(t, 0, played%3)
+		assert.Less(t, played, 30)
+		assert.Equal(t, -70, g.GetScore(PlayerInitialMiddlehand))
+		for i := 0; i < 3; i = i + 1 {
+			assert.Equal(t, 0, len(g.Playing().GetHand(i)))
+		}
+	})
+
+	t.Run("game with schwarz announced ends with the first trick of the defenders", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		assert.Nil(t, g.Declare(PlayerInitialMiddlehand, GameTypeGrand, GameModifierSchwarzAnnounced.Normalized(), nil))
+		played := testPlayOut(t, g.Playing(), g.PlayCard, -1)
+		assert.Equal(t, PhaseScored, g.Phase())
+		assert.Equal(t, LossReasonNoSchwarz, g.GetLossReason())
+		assert.Less(t, played, 30)
+		// remaining cards are credited to the declarer
+		assert.Equal(t, 120-g.Playing().GetWonCards(PlayerInitialForehand).Value()-g.Playing().GetWonCards(PlayerInitialRearhand).Value(), g.Playing().GetWonCards(PlayerInitialMiddlehand).Value())
+	})
+
+	t.Run("game without schwarz announced is played to the end", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		played := testPlayOut(t, g.Playing(), g.PlayCard, -1)
+		assert.Equal(t, PhaseScored, g.Phase())
+		assert.Equal(t, 30, played)
+		assert.Equal(t, 10, len(g.BlindedForPlayer(PlayerInitialForehand).Tricks))
+	})
+}
+
+func TestGameStateResign(t *testing.T) {
+	t.Run("reject resignation during bidding", func(t *testing.T) {
+		g := testGetBiddingPhaseGame(t)
+		assert.Equal(t, ErrWrongPhase, g.Resign(PlayerInitialForehand))
+	})
+
+	t.Run("reject resignation in junk game", func(t *testing.T) {
+		g := testGetJunkPhaseGame(t)
+		assert.Equal(t, ErrInvalidGame, g.Resign(PlayerInitialForehand))
+	})
+
+	t.Run("reject resignation of defender during declaration", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		assert.Equal(t, ErrWrongPhase, g.Resign(PlayerInitialForehand))
+		assert.Equal(t, PhaseDeclaration, g.Phase())
+	})
+
+	t.Run("declarer resigning during declaration loses the bid", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		assert.Nil(t, g.TakeSkat(PlayerInitialMiddlehand))
+		assert.Nil(t, g.Resign(PlayerInitialMiddlehand))
+		assert.Equal(t, PhaseScored, g.Phase())
+		assert.Equal(t, LossReasonResigned, g.GetLossReason())
+		expected := LeagueScoreDefinition().CalculateScore(18, PlayerInitialMiddlehand, false)
+		for i := 0; i < 3; i = i + 1 {
+			assert.Equal(t, expected[i], g.GetScore(i))
+		}
+		blinded := g.BlindedForPlayer(PlayerInitialForehand)
+		assert.Equal(t, InvalidGameType, blinded.GameType)
+		assert.Equal(t, 18, blinded.FinalGameValue)
+	})
+
+	t.Run("declarer resigning during play loses the game", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeSpades)
+		assert.Nil(t, g.Resign(PlayerInitialMiddlehand))
+		assert.Equal(t, PhaseScored, g.Phase())
+		assert.Equal(t, LossReasonResigned, g.GetLossReason())
+		assert.Less(t, g.GetScore(PlayerInitialMiddlehand), 0)
+		// the skat counts for the declarer, everything else for the defenders
+		assert.Equal(t, g.GetSkat().Value(), g.Playing().GetWonCards(PlayerInitialMiddlehand).Value())
+		// the defenders had not taken any tricks yet
+		assert.False(t, g.Modifiers().Test(GameModifierSchneider))
+		assert.False(t, g.Modifiers().Test(GameModifierSchwarz))
+		assert.True(t, g.BlindedForPlayer(PlayerInitialForehand).Players[PlayerInitialMiddlehand].Resigned)
+	})
+
+	t.Run("declarer out of schneider is not schneider after resigning", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeClubs)
+		declarer := PlayerInitialMiddlehand
+		for g.Phase() == PhasePlaying && g.Playing().GetWonCards(declarer).Value() <= 30 {
+			assert.Equal(t, 3, testPlayOut(t, g.Playing(), g.PlayCard, 3))
+		}
+		assert.Nil(t, g.Resign(declarer))
+		assert.Equal(t, PhaseScored, g.Phase())
+		assert.Equal(t, LossReasonResigned, g.GetLossReason())
+		assert.False(t, g.Modifiers().Test(GameModifierSchneider))
+		assert.False(t, g.Modifiers().Test(GameModifierSchwarz))
+		base, factor := g.rules.GameValue(g.players[declarer].Hand, GameTypeClubs, g.Modifiers())
+		assert.Equal(t, base*factor, g.GetGameValue())
+	})
+
+	t.Run("reject resigning twice", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeSpades)
+		assert.Nil(t, g.Resign(PlayerInitialForehand))
+		assert.Equal(t, ErrAlreadyResigned, g.Resign(PlayerInitialForehand))
+	})
+
+	t.Run("defenders concede once both resigned", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeDiamonds)
+		assert.Nil(t, g.Resign(PlayerInitialForehand))
+		assert.Equal(t, PhasePlaying, g.Phase())
+		assert.True(t, g.BlindedForPlayer(PlayerInitialMiddlehand).Players[PlayerInitialForehand].Resigned)
+
+		assert.Nil(t, g.Resign(PlayerInitialRearhand))
+		assert.Equal(t, PhaseScored, g.Phase())
+		assert.Equal(t, "", g.GetLossReason())
+		assert.Equal(t, 120, g.Playing().GetWonCards(PlayerInitialMiddlehand).Value())
+		assert.True(t, g.Modifiers().Test(GameModifierSchwarz))
+		assert.Greater(t, g.GetScore(PlayerInitialMiddlehand), 0)
+	})
+
+	t.Run("defenders conceding a null game lose it", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeNull)
+		assert.Nil(t, g.Resign(PlayerInitialForehand))
+		assert.Nil(t, g.Resign(PlayerInitialRearhand))
+		assert.Equal(t, PhaseScored, g.Phase())
+		assert.Equal(t, "", g.GetLossReason())
+		assert.Equal(t, 0, g.Playing().GetTrickCount(PlayerInitialMiddlehand))
+		assert.Greater(t, g.GetScore(PlayerInitialMiddlehand), 0)
+	})
+}
+
+func TestGameStatePeek(t *testing.T) {
+	t.Run("reject peek outside of playing phase", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		assert.Equal(t, ErrWrongPhase, g.Peek(PlayerInitialMiddlehand, PeekSkat))
+	})
+
+	t.Run("reject peek at last trick before first trick", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		assert.Equal(t, ErrNothingToPeek, g.Peek(PlayerInitialForehand, PeekLastTrick))
+	})
+
+	t.Run("reject unknown peek target", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		assert.Equal(t, ErrNothingToPeek, g.Peek(PlayerInitialForehand, PeekTarget("hand")))
+	})
+
+	t.Run("last trick is shown to the peeking player only", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		testPlayOut(t, g.Playing(), g.PlayCard, 3)
+		assert.Nil(t, g.Peek(PlayerInitialForehand, PeekLastTrick))
+
+		trick, winner := g.Playing().GetLastTrick()
+		blinded := g.BlindedForPlayer(PlayerInitialForehand)
+		assert.Equal(t, CardSet{trick[0], trick[1], trick[2]}, blinded.LastTrick)
+		assert.Equal(t, winner, blinded.LastTrickWinner)
+		assert.Equal(t, winner, blinded.CurrentForehand)
+
+		blinded = g.BlindedForPlayer(PlayerInitialRearhand)
+		assert.Nil(t, blinded.LastTrick)
+		assert.Equal(t, PlayerNone, blinded.LastTrickWinner)
+	})
+
+	t.Run("peek ends with the next card", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		testPlayOut(t, g.Playing(), g.PlayCard, 3)
+		assert.Nil(t, g.Peek(PlayerInitialForehand, PeekLastTrick))
+		player := g.Playing().GetCurrentPlayer()
+		assert.Nil(t, g.PlayCard(player, g.GetHand(player)[0]))
+		assert.Nil(t, g.BlindedForPlayer(PlayerInitialForehand).LastTrick)
+	})
+
+	t.Run("declarer can see pushed cards", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		skat := g.GetSkat()
+		assert.Nil(t, g.TakeSkat(PlayerInitialMiddlehand))
+		assert.Nil(t, g.Declare(PlayerInitialMiddlehand, GameTypeHearts, NoGameModifiers, skat))
+		assert.Equal(t, ErrNotYourTurn, g.Peek(PlayerInitialForehand, PeekSkat))
+		assert.Nil(t, g.Peek(PlayerInitialMiddlehand, PeekSkat))
+		assert.Equal(t, skat, g.BlindedForPlayer(PlayerInitialMiddlehand).PushedCards)
+		assert.Nil(t, g.BlindedForPlayer(PlayerInitialForehand).PushedCards)
+	})
+
+	t.Run("reject peek at skat in hand game", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeHearts)
+		assert.Equal(t, ErrNothingToPeek, g.Peek(PlayerInitialMiddlehand, PeekSkat))
+	})
+}
+
+func TestGameStateClaim(t *testing.T) {
+	t.Run("reject claim in junk game", func(t *testing.T) {
+		g := testGetJunkPhaseGame(t)
+		assert.Equal(t, ErrInvalidGame, g.Claim(PlayerInitialForehand, 0))
+	})
+
+	t.Run("reject claim during a trick", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		player := g.Playing().GetCurrentPlayer()
+		assert.Nil(t, g.PlayCard(player, g.GetHand(player)[0]))
+		assert.Equal(t, ErrInvalidClaim, g.Claim(PlayerInitialMiddlehand, 0))
+	})
+
+	t.Run("reject claim of more tricks than remain", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		assert.Equal(t, ErrInvalidClaim, g.Claim(PlayerInitialMiddlehand, 11))
+		assert.Equal(t, ErrInvalidClaim, g.Claim(PlayerInitialMiddlehand, -1))
+	})
+
+	t.Run("reject invalid claims in null game", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeNull)
+		assert.Equal(t, ErrInvalidClaim, g.Claim(PlayerInitialForehand, 0))
+		assert.Equal(t, ErrInvalidClaim, g.Claim(PlayerInitialMiddlehand, 1))
+	})
+
+	t.Run("reject claims which hold for any play", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		assert.Equal(t, ErrInvalidClaim, g.Claim(PlayerInitialMiddlehand, 0))
+		assert.Equal(t, ErrInvalidClaim, g.Claim(PlayerInitialForehand, 0))
+		assert.Equal(t, PhasePlaying, g.Phase())
+		assert.Nil(t, g.BlindedForPlayer(PlayerInitialMiddlehand).Claim)
+	})
+
+	t.Run("provable claim ends the game", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		assert.Nil(t, g.Claim(PlayerInitialForehand, 3))
+		assert.Equal(t, PhaseScored, g.Phase())
+		// the defenders take exactly the claimed tricks
+		defenderTricks := g.Playing().GetTrickCount(PlayerInitialForehand) + g.Playing().GetTrickCount(PlayerInitialRearhand)
+		assert.Equal(t, 3, defenderTricks)
+		assert.Equal(t, 7, g.Playing().GetTrickCount(PlayerInitialMiddlehand))
+	})
+
+	t.Run("pending claim blocks play", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		assert.Nil(t, g.Claim(PlayerInitialForehand, 10))
+		assert.Equal(t, PhasePlaying, g.Phase())
+		claim := g.BlindedForPlayer(PlayerInitialMiddlehand).Claim
+		assert.NotNil(t, claim)
+		assert.Equal(t, PlayerInitialForehand, claim.Player)
+		assert.Equal(t, 10, claim.Tricks)
+
+		player := g.Playing().GetCurrentPlayer()
+		assert.Equal(t, ErrClaimPending, g.PlayCard(player, g.GetHand(player)[0]))
+		assert.Equal(t, ErrClaimPending, g.Claim(PlayerInitialMiddlehand, 10))
+	})
+
+	t.Run("only the other side responds", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		assert.Equal(t, ErrNoClaim, g.RespondToClaim(PlayerInitialMiddlehand, true))
+		assert.Nil(t, g.Claim(PlayerInitialForehand, 10))
+		assert.Equal(t, ErrNotYourTurn, g.RespondToClaim(PlayerInitialForehand, true))
+		assert.Equal(t, ErrNotYourTurn, g.RespondToClaim(PlayerInitialRearhand, true))
+	})
+
+	t.Run("rejected claim is withdrawn", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		assert.Nil(t, g.Claim(PlayerInitialForehand, 10))
+		assert.Nil(t, g.RespondToClaim(PlayerInitialMiddlehand, false))
+		assert.Nil(t, g.BlindedForPlayer(PlayerInitialMiddlehand).Claim)
+		player := g.Playing().GetCurrentPlayer()
+		assert.Nil(t, g.PlayCard(player, g.GetHand(player)[0]))
+	})
+
+	t.Run("accepted claim ends the game", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		assert.Nil(t, g.Claim(PlayerInitialMiddlehand, 10))
+		assert.Nil(t, g.RespondToClaim(PlayerInitialForehand, true))
+		assert.Equal(t, PhasePlaying, g.Phase())
+		assert.True(t, g.BlindedForPlayer(PlayerInitialMiddlehand).Claim.Accepted[PlayerInitialForehand])
+		assert.Nil(t, g.RespondToClaim(PlayerInitialRearhand, true))
+		assert.Equal(t, PhaseScored, g.Phase())
+		for i := 0; i < 3; i = i + 1 {
+			assert.Equal(t, 0, len(g.Playing().GetHand(i)))
+		}
+	})
+
+	t.Run("accepted claim scores the claimed tricks", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		holds, err := g.Playing().VerifyClaim(PlayerInitialMiddlehand, 10)
+		assert.Nil(t, err)
+		assert.False(t, holds)
+		assert.Nil(t, g.Claim(PlayerInitialMiddlehand, 10))
+		assert.Nil(t, g.RespondToClaim(PlayerInitialForehand, true))
+		assert.Nil(t, g.RespondToClaim(PlayerInitialRearhand, true))
+		assert.Equal(t, PhaseScored, g.Phase())
+		assert.Equal(t, 10, g.Playing().GetTrickCount(PlayerInitialMiddlehand))
+		assert.True(t, g.Modifiers().Test(GameModifierSchwarz))
+	})
+}
+
+func testGetDealerBiddingPhaseGame(t *testing.T) *GameState {
+	g, err := NewGame(true, LeagueScoreDefinition(), StandardRuleSet())
+	assert.Nil(t, err)
+	assert.True(t, g.WithDealer())
+	assert.Nil(t, g.ForceServerSeed([]byte{}))
+	testCommitSeeds(t, g)
+	assert.Nil(t, g.CommitDealerSeed(CommitToSeed([]byte{1})))
+	assert.Nil(t, g.SetSeed(PlayerInitialForehand, []byte{}))
+	assert.Nil(t, g.SetSeed(PlayerInitialMiddlehand, []byte{}))
+	assert.Nil(t, g.SetSeed(PlayerInitialRearhand, []byte{}))
+	assert.Equal(t, PhaseInit, g.Phase())
+	assert.False(t, g.BlindedForDealer().DealerSeedProvided)
+	assert.Nil(t, g.SetDealerSeed([]byte{1}))
+	assert.Equal(t, PhaseBidding, g.Phase())
+	return g
+}
+
+func TestGameStateDealer(t *testing.T) {
+	t.Run("reject watching without dealer", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		assert.Equal(t, ErrNoDealer, g.WatchHand(PlayerInitialMiddlehand))
+		assert.False(t, g.BlindedForPlayer(PlayerInitialForehand).WithDealer)
+	})
+
+	t.Run("dealer seed changes the deal", func(t *testing.T) {
+		g := testGetDealerBiddingPhaseGame(t)
+		other := testGetBiddingPhaseGame(t)
+		assert.NotEqual(t, other.GetHand(PlayerInitialForehand), g.GetHand(PlayerInitialForehand))
+		assert.True(t, g.BlindedForPlayer(PlayerInitialForehand).DealerSeedProvided)
+	})
+
+	t.Run("reject watching during bidding", func(t *testing.T) {
+		g := testGetDealerBiddingPhaseGame(t)
+		assert.Equal(t, ErrWrongPhase, g.WatchHand(PlayerInitialForehand))
+	})
+
+	t.Run("dealer sees the watched hand only", func(t *testing.T) {
+		g := testGetDealerBiddingPhaseGame(t)
+		testWinBidding(t, g)
+
+		blinded := g.BlindedForDealer()
+		assert.Nil(t, blinded.Hand)
+		assert.Equal(t, PlayerNone, blinded.WatchedPlayer)
+
+		assert.Equal(t, ErrInvalidPlayer, g.WatchHand(3))
+		assert.Nil(t, g.WatchHand(PlayerInitialMiddlehand))
+		assert.Equal(t, ErrAlreadyWatching, g.WatchHand(PlayerInitialForehand))
+
+		blinded = g.BlindedForDealer()
+		assert.Equal(t, g.GetHand(PlayerInitialMiddlehand), blinded.Hand)
+		assert.Equal(t, PlayerInitialMiddlehand, blinded.WatchedPlayer)
+		assert.Equal(t, PlayerInitialMiddlehand, g.BlindedForPlayer(PlayerInitialRearhand).WatchedPlayer)
+
+		assert.Nil(t, g.Declare(PlayerInitialMiddlehand, GameTypeGrand, NoGameModifiers, nil))
+		player := g.Playing().GetCurrentPlayer()
+		assert.Nil(t, g.PlayCard(player, g.GetHand(player)[0]))
+		assert.Equal(t, 10, len(g.BlindedForDealer().Hand))
+		assert.Equal(t, g.GetHand(PlayerInitialMiddlehand), g.BlindedForDealer().Hand)
+	})
+}
+
+func TestGameStatePassedIn(t *testing.T) {
+	getPassedInGame := func(t *testing.T) *GameState {
+		rules := StandardRuleSet()
+		rules.AllPass = AllPassRedeal
+		g := testNewDealtGame(t, rules)
+		testPassBidding(t, g)
+		return g
+	}
+
+	t.Run("all pass ends the game without a score", func(t *testing.T) {
+		g := getPassedInGame(t)
+		assert.Equal(t, PhasePassedIn, g.Phase())
+		assert.Equal(t, PlayerNone, g.Declarer())
+		assert.Equal(t, 0, g.GetGameValue())
+		for i := 0; i < 3; i = i + 1 {
+			assert.Equal(t, 0, g.GetScore(i))
+		}
+		blinded := g.BlindedForPlayer(PlayerInitialForehand)
+		assert.Equal(t, PhasePassedIn, blinded.Phase)
+		assert.Equal(t, PlayerNone, blinded.Declarer)
+	})
+
+	t.Run("no actions after passing in", func(t *testing.T) {
+		g := getPassedInGame(t)
+		assert.Equal(t, ErrWrongPhase, g.TakeSkat(PlayerInitialForehand))
+		assert.Equal(t, ErrWrongPhase, g.Resign(PlayerInitialForehand))
+		assert.Equal(t, ErrWrongPhase, g.EvaluateGame())
+	})
+
+	t.Run("passed in game is recorded with zero value", func(t *testing.T) {
+		s := NewScoreSheet(3)
+		assert.Nil(t, s.Record(getPassedInGame(t), [3]int{0, 1, 2}))
+		assert.True(t, s.Entries[0].PassedIn)
+		assert.Equal(t, 0, s.Entries[0].GameValue)
+		assert.Equal(t, PlayerNone, s.Entries[0].Declarer)
+		assert.Equal(t, []int{0, 0, 0}, s.Totals)
+	})
+}
+
+func testGetJunkPushingPhaseGame(t *testing.T) *GameState {
+	rules := StandardRuleSet()
+	rules.Schieberamsch = true
+	g := testNewDealtGame(t, rules)
+	testPassBidding(t, g)
+	assert.Equal(t, PhaseJunkPushing, g.Phase())
+	return g
+}
+
+func TestGameStateSchieberamsch(t *testing.T) {
+	t.Run("players decide on the skat in turn", func(t *testing.T) {
+		g := testGetJunkPushingPhaseGame(t)
+		assert.Equal(t, PlayerInitialForehand, g.JunkPushingPlayer())
+		blinded := g.BlindedForPlayer(PlayerInitialMiddlehand)
+		assert.Equal(t, PlayerInitialForehand, blinded.CurrentPlayer)
+		assert.Equal(t, GameTypeJunk, blinded.GameType)
+		assert.Equal(t, PlayerNone, blinded.Declarer)
+		assert.Equal(t, 2, blinded.SkatCards)
+
+		assert.Equal(t, ErrNotYourTurn, g.PassSkatOn(PlayerInitialMiddlehand))
+		assert.Equal(t, ErrNotYourTurn, g.TakeSkat(PlayerInitialRearhand))
+		assert.Nil(t, g.PassSkatOn(PlayerInitialForehand))
+		assert.Equal(t, PlayerInitialMiddlehand, g.JunkPushingPlayer())
+		assert.Equal(t, []SkatPass{{Player: PlayerInitialForehand, Pushed: false}}, g.BlindedForPlayer(PlayerInitialRearhand).SkatPasses)
+	})
+
+	t.Run("taking the skat requires a push", func(t *testing.T) {
+		g := testGetJunkPushingPhaseGame(t)
+		skat := g.GetSkat()
+		assert.Nil(t, g.TakeSkat(PlayerInitialForehand))
+		assert.Equal(t, 12, len(g.GetHand(PlayerInitialForehand)))
+		assert.Equal(t, 0, g.BlindedForPlayer(PlayerInitialForehand).SkatCards)
+		assert.Equal(t, ErrWrongPhase, g.TakeSkat(PlayerInitialForehand))
+		assert.Equal(t, ErrInvalidPush, g.PassSkatOn(PlayerInitialForehand))
+		assert.Equal(t, ErrInvalidPush, g.PushSkatOn(PlayerInitialForehand, skat[:1]))
+
+		pushset := CardSet{}
+		for _, card := range g.GetHand(PlayerInitialForehand) {
+			if card.Type != CardJack && len(pushset) < 2 {
+				pushset = append(pushset, card)
+			}
+		}
+		assert.Nil(t, g.PushSkatOn(PlayerInitialForehand, pushset))
+		assert.Equal(t, 10, len(g.GetHand(PlayerInitialForehand)))
+		assert.Equal(t, pushset, g.GetSkat())
+		assert.Equal(t, 1, g.JunkPushes())
+	})
+
+	t.Run("reject pushing jacks or foreign cards", func(t *testing.T) {
+		g := testGetJunkPushingPhaseGame(t)
+		jacks := CardSet{CardJack.As(SuitClubs), CardJack.As(SuitSpades)}
+		foreign := CardSet{}
+		for _, card := range g.GetHand(PlayerInitialMiddlehand) {
+			if card.Type != CardJack && len(foreign) < 2 {
+				foreign = append(foreign, card)
+			}
+		}
+		assert.Equal(t, ErrInvalidPush, g.PushSkatOn(PlayerInitialForehand, jacks))
+		assert.Equal(t, ErrInvalidPush, g.PushSkatOn(PlayerInitialForehand, foreign))
+		assert.Equal(t, PlayerInitialForehand, g.JunkPushingPlayer())
+	})
+
+	t.Run("play starts after the last decision", func(t *testing.T) {
+		g := testGetJunkPushingPhaseGame(t)
+		assert.Nil(t, g.PassSkatOn(PlayerInitialForehand))
+		assert.Nil(t, g.PassSkatOn(PlayerInitialMiddlehand))
+		assert.Nil(t, g.PassSkatOn(PlayerInitialRearhand))
+		assert.Equal(t, PhasePlaying, g.Phase())
+		assert.Equal(t, GameTypeJunk, g.GameType())
+		assert.Equal(t, PlayerNone, g.JunkPushingPlayer())
+		assert.Equal(t, 3, len(g.BlindedForPlayer(PlayerInitialForehand).SkatPasses))
+	})
+
+	t.Run("each push doubles the result", func(t *testing.T) {
+		plain := testGetJunkPushingPhaseGame(t)
+		for i := 0; i < 3; i = i + 1 {
+			assert.Nil(t, plain.PassSkatOn(i))
+		}
+		testPlayOut(t, plain.Playing(), plain.PlayCard, -1)
+
+		pushed := testGetJunkPushingPhaseGame(t)
+		skat := pushed.GetSkat()
+		// pushing the skat back unchanged keeps the cards of the plain game
+		assert.Nil(t, pushed.PushSkatOn(PlayerInitialForehand, skat))
+		assert.Nil(t, pushed.PushSkatOn(PlayerInitialMiddlehand, skat))
+		assert.Nil(t, pushed.PassSkatOn(PlayerInitialRearhand))
+		testPlayOut(t, pushed.Playing(), pushed.PlayCard, -1)
+
+		assert.Equal(t, PhaseScored, pushed.Phase())
+		assert.Equal(t, 4*plain.GetGameValue(), pushed.GetGameValue())
+		for i := 0; i < 3; i = i + 1 {
+			assert.Equal(t, 4*plain.GetScore(i), pushed.GetScore(i))
+		}
+	})
+}
+
+func testGetExchangePhaseGame(t *testing.T) *GameState {
+	rules := StandardRuleSet()
+	rules.Revolution = true
+	g := testGetRulesPlayingPhaseGame(t, rules, GameTypeRevolution, NoGameModifiers)
+	assert.Equal(t, PhaseExchange, g.Phase())
+	return g
+}
+
+func TestGameStateRevolution(t *testing.T) {
+	t.Run("requires the house rule", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		assert.Equal(t, ErrRuleDisabled, g.Declare(PlayerInitialMiddlehand, GameTypeRevolution, NoGameModifiers, nil))
+	})
+
+	t.Run("requires a hand game", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.Revolution = true
+		g := testNewDealtGame(t, rules)
+		testWinBidding(t, g)
+		assert.Nil(t, g.TakeSkat(PlayerInitialMiddlehand))
+		assert.Equal(t, ErrInvalidGame, g.Declare(PlayerInitialMiddlehand, GameTypeRevolution, NoGameModifiers, g.GetSkat()))
+	})
+
+	t.Run("declarer opens the hand", func(t *testing.T) {
+		g := testGetExchangePhaseGame(t)
+		assert.Equal(t, GameTypeRevolution, g.GameType())
+		assert.Equal(t, GameModifierHand|GameModifierOuvert, g.Modifiers())
+		blinded := g.BlindedForPlayer(PlayerInitialForehand)
+		assert.Equal(t, GameTypeRevolution, blinded.GameType)
+		assert.Equal(t, PlayerInitialMiddlehand, blinded.Declarer)
+		assert.Equal(t, g.GetHand(PlayerInitialMiddlehand), blinded.OpenHands[PlayerInitialMiddlehand])
+	})
+
+	t.Run("defenders see their combined hands", func(t *testing.T) {
+		g := testGetExchangePhaseGame(t)
+		blinded := g.BlindedForPlayer(PlayerInitialForehand)
+		assert.Equal(t, g.GetHand(PlayerInitialForehand), blinded.Hand)
+		assert.Equal(t, g.GetHand(PlayerInitialRearhand), blinded.PartnerHand)
+		blinded = g.BlindedForPlayer(PlayerInitialRearhand)
+		assert.Equal(t, g.GetHand(PlayerInitialForehand), blinded.PartnerHand)
+		assert.Nil(t, g.BlindedForPlayer(PlayerInitialMiddlehand).PartnerHand)
+	})
+
+	t.Run("defenders swap cards", func(t *testing.T) {
+		g := testGetExchangePhaseGame(t)
+		give := g.GetHand(PlayerInitialForehand)[:2]
+		take := g.GetHand(PlayerInitialRearhand)[:2]
+		assert.Equal(t, ErrNotYourTurn, g.ExchangeCards(PlayerInitialMiddlehand, nil, nil))
+		assert.Equal(t, ErrInvalidExchange, g.ExchangeCards(PlayerInitialForehand, give, take[:1]))
+		assert.Equal(t, ErrInvalidExchange, g.ExchangeCards(PlayerInitialForehand, take, give))
+		assert.Nil(t, g.ExchangeCards(PlayerInitialForehand, give, take))
+		assert.Equal(t, ErrAlreadyExchanged, g.ExchangeCards(PlayerInitialForehand, nil, nil))
+		assert.True(t, g.BlindedForPlayer(PlayerInitialRearhand).Players[PlayerInitialForehand].Exchanged)
+
+		forehand := g.GetHand(PlayerInitialForehand)
+		rearhand := g.GetHand(PlayerInitialRearhand)
+		assert.Equal(t, 10, len(forehand))
+		assert.Equal(t, 10, len(rearhand))
+		for i := range give {
+			assert.True(t, forehand.Contains(take[i]))
+			assert.True(t, rearhand.Contains(give[i]))
+		}
+		assert.Equal(t, PhaseExchange, g.Phase())
+
+		assert.Nil(t, g.ExchangeCards(PlayerInitialRearhand, nil, nil))
+		assert.Equal(t, PhasePlaying, g.Phase())
+		assert.Equal(t, GameTypeRevolution, g.Playing().GameType())
+		assert.Equal(t, forehand, g.GetHand(PlayerInitialForehand))
+		assert.Equal(t, ErrWrongPhase, g.ExchangeCards(PlayerInitialRearhand, nil, nil))
+	})
+
+	t.Run("rejects more cards than in the hand", func(t *testing.T) {
+		g := testGetExchangePhaseGame(t)
+		give := append(g.GetHand(PlayerInitialForehand), g.GetHand(PlayerInitialForehand)[0])
+		take := append(g.GetHand(PlayerInitialRearhand), g.GetHand(PlayerInitialRearhand)[0])
+		assert.Equal(t, ErrInvalidExchange, g.ExchangeCards(PlayerInitialForehand, give, take))
+		assert.Equal(t, ErrInvalidExchange, g.ExchangeCards(PlayerInitialForehand, give[:10], take))
+		assert.Equal(t, 10, len(g.GetHand(PlayerInitialForehand)))
+		assert.Equal(t, 10, len(g.GetHand(PlayerInitialRearhand)))
+	})
+
+	t.Run("scored like null", func(t *testing.T) {
+		g := testGetExchangePhaseGame(t)
+		assert.Nil(t, g.ExchangeCards(PlayerInitialForehand, nil, nil))
+		assert.Nil(t, g.ExchangeCards(PlayerInitialRearhand, nil, nil))
+		assert.NotNil(t, g.BlindedForPlayer(PlayerInitialForehand).OpenHands)
+		testPlayOut(t, g.Playing(), g.PlayCard, -1)
+		assert.Equal(t, PhaseScored, g.Phase())
+		assert.Equal(t, LossReasonNotNull, g.GetLossReason())
+		assert.Equal(t, 92, g.GetGameValue())
+		assert.Equal(t, -184, g.GetScore(PlayerInitialMiddlehand))
+	})
+}
+
+func TestGameStateReveal(t *testing.T) {
+	t.Run("nothing is revealed before the end", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		assert.Nil(t, g.Reveal())
+		assert.Nil(t, g.BlindedForPlayer(PlayerInitialForehand).Reveal)
+	})
+
+	t.Run("reveals the deal and the pushed cards", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		hands := make([]CardSet, 3)
+		for i := range hands {
+			hands[i] = g.GetHand(i)
+		}
+		skat := g.GetSkat()
+		assert.Nil(t, g.TakeSkat(PlayerInitialMiddlehand))
+		pushed := g.GetHand(PlayerInitialMiddlehand)[:2].Copy()
+		assert.Nil(t, g.Declare(PlayerInitialMiddlehand, GameTypeGrand, NoGameModifiers, pushed))
+		testPlayOut(t, g.Playing(), g.PlayCard, -1)
+
+		for i := 0; i < 3; i = i + 1 {
+			reveal := g.BlindedForPlayer(i).Reveal
+			assert.NotNil(t, reveal)
+			assert.Equal(t, hands, reveal.Hands)
+			assert.Equal(t, skat, reveal.Skat)
+			assert.Equal(t, pushed, reveal.Pushed)
+		}
+	})
+
+	t.Run("reveals the skat of a hand game", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		skat := g.GetSkat()
+		testPlayOut(t, g.Playing(), g.PlayCard, -1)
+		reveal := g.Reveal()
+		assert.Equal(t, skat, reveal.Skat)
+		assert.Nil(t, reveal.Pushed)
+		assert.Equal(t, 10, len(reveal.Hands[PlayerInitialMiddlehand]))
+	})
+
+	t.Run("reveals the hands before the schieberamsch", func(t *testing.T) {
+		g := testGetJunkPushingPhaseGame(t)
+		hand := g.GetHand(PlayerInitialForehand)
+		skat := g.GetSkat()
+		pushed := CardSet{}
+		assert.Nil(t, g.TakeSkat(PlayerInitialForehand))
+		for _, card := range g.GetHand(PlayerInitialForehand) {
+			if card.Type != CardJack && len(pushed) < 2 {
+				pushed = append(pushed, card)
+			}
+		}
+		assert.Nil(t, g.PushSkatOn(PlayerInitialForehand, pushed))
+		assert.Nil(t, g.PassSkatOn(PlayerInitialMiddlehand))
+		assert.Nil(t, g.PassSkatOn(PlayerInitialRearhand))
+		testPlayOut(t, g.Playing(), g.PlayCard, -1)
+
+		reveal := g.Reveal()
+		assert.Equal(t, hand, reveal.Hands[PlayerInitialForehand])
+		assert.Equal(t, skat, reveal.Skat)
+		assert.Equal(t, pushed, reveal.Pushed)
+	})
+}
+
+func TestGameStateLegalCards(t *testing.T) {
+	t.Run("sent to the player on turn only", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeClubs)
+		assert.Equal(t, g.GetHand(PlayerInitialForehand), g.BlindedForPlayer(PlayerInitialForehand).LegalCards)
+		assert.Nil(t, g.BlindedForPlayer(PlayerInitialMiddlehand).LegalCards)
+		assert.Nil(t, g.BlindedForDealer().LegalCards)
+	})
+
+	t.Run("none outside of play", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		assert.Nil(t, g.LegalCards(PlayerInitialForehand))
+	})
+
+	t.Run("none while a claim is pending", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeClubs)
+		assert.Nil(t, g.Claim(PlayerInitialMiddlehand, 10))
+		assert.Equal(t, PhasePlaying, g.Phase())
+		assert.Nil(t, g.LegalCards(PlayerInitialForehand))
+	})
+}
@@ -0,0 +1,393 @@
+package skat
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+var (
+	ErrInvalidKey     = errors.New("invalid mental poker key")
+	ErrInvalidElement = errors.New("value is not a valid encrypted card")
+	ErrInvalidProof   = errors.New("invalid proof")
+	ErrNotACard       = errors.New("value does not decrypt to a card")
+)
+
+const (
+	// Size of encoded group elements and exponents in bytes
+	MentalPokerElementSize = 256
+
+	mentalPokerDomain     = "webskat mental poker v1"
+	mentalPokerCardDomain = "webskat mental poker v1 card"
+)
+
+var (
+	// The 2048-bit MODP group of RFC 3526. p is a safe prime, p = 2q + 1;
+	// all values are taken from the subgroup of quadratic residues, which has
+	// the prime order q.
+	mentalPokerP, _ = new(big.Int).SetString(
+		"FFFFFFFFFFFFFFFFC90FDAA22168C234C4C6628B80DC1CD1"+
+			"29024E088A67CC74020BBEA63B139B22514A08798E3404DD"+
+			"EF9519B3CD3A431B302B0A6DF25F14374FE1356D6D51C245"+
+			"E485B576625E7EC6F44C42E9A637ED6B0BFF5CB6F406B7ED"+
+			"EE386BFB5A899FA5AE9F24117C4B1FE649286651ECE45B3D"+
+			"C2007CB8A163BF0598DA48361C55D39A69163FA8FD24CF5F"+
+			"83655D23DCA3AD961C62F356208552BB9ED529077096966D"+
+			"670C354E4ABC9804F1746C08CA18217C32905E462E36CE3B"+
+			"E39E772C180E86039B2783A2EC07A28FB5C55DF06F4C52C9"+
+			"DE2BCBF6955817183995497CEA956AE515D2261898FA0510"+
+			"15728E5A8AACAA68FFFFFFFFFFFFFFFF",
+		16,
+	)
+	mentalPokerQ = new(big.Int).Rsh(mentalPokerP, 1)
+	// Generator of the subgroup, used for the public keys
+	mentalPokerG = big.NewInt(4)
+	// Plain group elements of the cards, in the order of NewCardDeck
+	mentalPokerCards = cardElements()
+)
+
+// Proof that two values were raised to the same secret exponent
+//
+// This is a non-interactive Chaum-Pedersen proof: it shows that
+// log_g(publicKey) = log_base(result) without revealing the exponent.
+type EqualityProof struct {
+	Challenge []byte `json:"c"`
+	Response  []byte `json:"z"`
+}
+
+// A key for the commutative encryption of cards
+//
+// Cards are encrypted by raising them to the secret exponent; encryptions by
+// several players can be removed in any order.
+type MentalPokerKey struct {
+	e *big.Int
+	d *big.Int
+}
+
+func encodeElement(x *big.Int) []byte {
+	return x.FillBytes(make([]byte, MentalPokerElementSize))
+}
+
+// Decode an element of the subgroup of quadratic residues
+func decodeElement(data []byte) (*big.Int, error) {
+	if len(data) != MentalPokerElementSize {
+		return nil, ErrInvalidElement
+	}
+	x := new(big.Int).SetBytes(data)
+	if x.Cmp(big.NewInt(1)) <= 0 || x.Cmp(mentalPokerP) >= 0 {
+		return nil, ErrInvalidElement
+	}
+	if big.Jacobi(x, mentalPokerP) != 1 {
+		return nil, ErrInvalidElement
+	}
+	return x, nil
+}
+
+func expElement(x *big.Int, exponent *big.Int) *big.Int {
+	return new(big.Int).Exp(x, exponent, mentalPokerP)
+}
+
+// Return the index of the card in the order of NewCardDeck
+func cardIndex(c Card) int {
+	for i, card := range NewCardDeck() {
+		if card == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// Hash a card index into the subgroup of quadratic residues
+//
+// The encryption keeps products, (ab)^e = a^e b^e, so the cards must not have
+// known relations to each other or to the generator; otherwise, the
+// encrypted cards could be told apart without any key. Hashing gives
+// independent elements, which are squared to land in the subgroup.
+func hashToElement(index int) *big.Int {
+	for counter := 0; ; counter = counter + 1 {
+		var digest []byte
+		for block := 0; len(digest) < MentalPokerElementSize+sha256.Size; block = block + 1 {
+			h := sha256.New()
+			h.Write([]byte(mentalPokerCardDomain))
+			h.Write([]byte{byte(index), byte(counter), byte(block)})
+			digest = h.Sum(digest)
+		}
+		x := new(big.Int).SetBytes(digest)
+		x.Mod(x, mentalPokerP)
+		x.Mul(x, x).Mod(x, mentalPokerP)
+		if x.Cmp(big.NewInt(1)) > 0 && x.Cmp(mentalPokerG) != 0 {
+			return x
+		}
+	}
+}
+
+func cardElements() []*big.Int {
+	deck := NewCardDeck()
+	result := make([]*big.Int, len(deck))
+	for i := range deck {
+		result[i] = hashToElement(i)
+	}
+	return result
+}
+
+// Return the plain group element which stands for the card
+//
+// Returns nil if the value is not a card.
+func cardElement(c Card) *big.Int {
+	i := cardIndex(c)
+	if i < 0 {
+		return nil
+	}
+	return new(big.Int).Set(mentalPokerCards[i])
+}
+
+// Return the cards of NewCardDeck as plain, unencrypted group elements
+//
+// This is the input of the first shuffle.
+func MentalPokerDeck() [][]byte {
+	deck := NewCardDeck()
+	result := make([][]byte, len(deck))
+	for i, card := range deck {
+		result[i] = encodeElement(cardElement(card))
+	}
+	return result
+}
+
+// Map a decrypted group element back to its card
+func elementCard(x *big.Int) (Card, error) {
+	for i, card := range NewCardDeck() {
+		if mentalPokerCards[i].Cmp(x) == 0 {
+			return card, nil
+		}
+	}
+	return Card{}, ErrNotACard
+}
+
+func randomExponent() (*big.Int, error) {
+	x, err := rand.Int(rand.Reader, new(big.Int).Sub(mentalPokerQ, big.NewInt(1)))
+	if err != nil {
+		return nil, err
+	}
+	return x.Add(x, big.NewInt(1)), nil
+}
+
+func newMentalPokerKey(e *big.Int) *MentalPokerKey {
+	return &MentalPokerKey{
+		e: e,
+		d: new(big.Int).ModInverse(e, mentalPokerQ),
+	}
+}
+
+func GenerateMentalPokerKey() (*MentalPokerKey, error) {
+	e, err := randomExponent()
+	if err != nil {
+		return nil, err
+	}
+	return newMentalPokerKey(e), nil
+}
+
+// Load a key revealed with Bytes
+func ParseMentalPokerKey(data []byte) (*MentalPokerKey, error) {
+	if len(data) != MentalPokerElementSize {
+		return nil, ErrInvalidKey
+	}
+	e := new(big.Int).SetBytes(data)
+	if e.Sign() <= 0 || e.Cmp(mentalPokerQ) >= 0 {
+		return nil, ErrInvalidKey
+	}
+	return newMentalPokerKey(e), nil
+}
+
+// Return the secret exponent; it must only be revealed after the game
+func (k *MentalPokerKey) Bytes() []byte {
+	return encodeElement(k.e)
+}
+
+// Return the public key, which the proofs of the key holder are checked
+// against
+func (k *MentalPokerKey) Public() []byte {
+	return encodeElement(expElement(mentalPokerG, k.e))
+}
+
+// Test whether the key belongs to the public key
+func (k *MentalPokerKey) Matches(publicKey []byte) bool {
+	y, err := decodeElement(publicKey)
+	if err != nil {
+		return false
+	}
+	return expElement(mentalPokerG, k.e).Cmp(y) == 0
+}
+
+func proofChallenge(values ...*big.Int) *big.Int {
+	h := sha256.New()
+	h.Write([]byte(mentalPokerDomain))
+	for _, value := range values {
+		h.Write(encodeElement(value))
+	}
+	c := new(big.Int).SetBytes(h.Sum(nil))
+	return c.Mod(c, mentalPokerQ)
+}
+
+// Prove that result = base^e for the exponent of the key
+func (k *MentalPokerKey) prove(base *big.Int, result *big.Int) (*EqualityProof, error) {
+	r, err := randomExponent()
+	if err != nil {
+		return nil, err
+	}
+	y := expElement(mentalPokerG, k.e)
+	c := proofChallenge(mentalPokerG, y, base, result, expElement(mentalPokerG, r), expElement(base, r))
+	z := new(big.Int).Mul(c, k.e)
+	z.Add(z, r)
+	z.Mod(z, mentalPokerQ)
+	return &EqualityProof{
+		Challenge: c.Bytes(),
+		Response:  encodeElement(z),
+	}, nil
+}
+
+// Recompute the commitment of the prover from the response, i.e.
+// base^z * value^-c
+func proofCommitment(base *big.Int, value *big.Int, c *big.Int, z *big.Int) *big.Int {
+	t := expElement(value, c)
+	t.ModInverse(t, mentalPokerP)
+	t.Mul(t, expElement(base, z))
+	return t.Mod(t, mentalPokerP)
+}
+
+// Check a proof that result = base^e for the exponent e of the public key
+func verifyEqualityProof(publicKey []byte, base *big.Int, result *big.Int, proof *EqualityProof) error {
+	if proof == nil || len(proof.Response) != MentalPokerElementSize {
+		return ErrInvalidProof
+	}
+	y, err := decodeElement(publicKey)
+	if err != nil {
+		return ErrInvalidKey
+	}
+	c := new(big.Int).SetBytes(proof.Challenge)
+	z := new(big.Int).SetBytes(proof.Response)
+	if c.Cmp(mentalPokerQ) >= 0 || z.Cmp(mentalPokerQ) >= 0 {
+		return ErrInvalidProof
+	}
+	expected := proofChallenge(
+		mentalPokerG, y, base, result,
+		proofCommitment(mentalPokerG, y, c, z),
+		proofCommitment(base, result, c, z),
+	)
+	if expected.Cmp(c) != 0 {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// Encrypt all cards of a deck with the key and shuffle them
+//
+// The deck must consist of distinct encrypted cards.
+func (k *MentalPokerKey) ShuffleDeck(deck [][]byte) ([][]byte, error) {
+	result := make([][]byte, len(deck))
+	for i, value := range deck {
+		x, err := decodeElement(value)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = encodeElement(expElement(x, k.e))
+	}
+	for i := len(result) - 1; i > 0; i = i - 1 {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return nil, err
+		}
+		result[i], result[j.Int64()] = result[j.Int64()], result[i]
+	}
+	return result, nil
+}
+
+// Remove the encryption of the key from a card
+//
+// The proof shows that the stripped value, raised to the exponent of the
+// key, gives the original value.
+func (k *MentalPokerKey) Strip(value []byte) ([]byte, *EqualityProof, error) {
+	x, err := decodeElement(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	stripped := expElement(x, k.d)
+	proof, err := k.prove(stripped, x)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encodeElement(stripped), proof, nil
+}
+
+// Check that a card was stripped with the key of the public key
+func VerifyStrip(publicKey []byte, value []byte, stripped []byte, proof *EqualityProof) error {
+	x, err := decodeElement(value)
+	if err != nil {
+		return err
+	}
+	s, err := decodeElement(stripped)
+	if err != nil {
+		return err
+	}
+	return verifyEqualityProof(publicKey, s, x, proof)
+}
+
+// Decrypt a card which is only encrypted with this key
+func (k *MentalPokerKey) Decrypt(value []byte) (Card, error) {
+	x, err := decodeElement(value)
+	if err != nil {
+		return Card{}, err
+	}
+	return elementCard(expElement(x, k.d))
+}
+
+// Prove that a value which is only encrypted with this key holds the card
+func (k *MentalPokerKey) ProveCard(value []byte, card Card) (*EqualityProof, error) {
+	m := cardElement(card)
+	if m == nil {
+		return nil, ErrNotACard
+	}
+	x, err := decodeElement(value)
+	if err != nil {
+		return nil, err
+	}
+	return k.prove(m, x)
+}
+
+// Check that a value which is only encrypted with the key of the public key
+// holds the card
+func VerifyCard(publicKey []byte, value []byte, card Card, proof *EqualityProof) error {
+	m := cardElement(card)
+	if m == nil {
+		return ErrNotACard
+	}
+	x, err := decodeElement(value)
+	if err != nil {
+		return err
+	}
+	return verifyEqualityProof(publicKey, m, x, proof)
+}
+
+// Test whether the output of a shuffle is the input encrypted with the key,
+// in any order
+func (k *MentalPokerKey) VerifyShuffle(input [][]byte, output [][]byte) bool {
+	if len(input) != len(output) {
+		return false
+	}
+	expected := make(map[string]int)
+	for _, value := range input {
+		x, err := decodeElement(value)
+		if err != nil {
+			return false
+		}
+		expected[string(encodeElement(expElement(x, k.e)))]++
+	}
+	for _, value := range output {
+		key := string(value)
+		if expected[key] == 0 {
+			return false
+		}
+		expected[key]--
+	}
+	return true
+}
@@ -0,0 +1,174 @@
+package skat
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testMentalPokerKeys(t *testing.T) [3]*MentalPokerKey {
+	var result [3]*MentalPokerKey
+	for i := range result {
+		key, err := GenerateMentalPokerKey()
+		assert.Nil(t, err)
+		result[i] = key
+	}
+	return result
+}
+
+func TestMentalPokerGroup(t *testing.T) {
+	assert.True(t, mentalPokerP.ProbablyPrime(20))
+	assert.True(t, mentalPokerQ.ProbablyPrime(20))
+	for _, card := range NewCardDeck() {
+		card2, err := elementCard(cardElement(card))
+		assert.Nil(t, err)
+		assert.Equal(t, card, card2)
+	}
+}
+
+// Test whether any product of two values, a*b mod p, is one of the values
+func testHasProductRelation(values []*big.Int) bool {
+	known := make(map[string]bool)
+	for _, value := range values {
+		known[value.String()] = true
+	}
+	for i := range values {
+		for j := i; j < len(values); j = j + 1 {
+			product := new(big.Int).Mul(values[i], values[j])
+			product.Mod(product, mentalPokerP)
+			if known[product.String()] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestMentalPokerCards(t *testing.T) {
+	t.Run("are distinct from each other and the generator", func(t *testing.T) {
+		seen := make(map[string]bool)
+		for _, card := range NewCardDeck() {
+			m := cardElement(card)
+			assert.Equal(t, 1, big.Jacobi(m, mentalPokerP))
+			assert.NotEqual(t, 0, m.Cmp(mentalPokerG))
+			assert.False(t, seen[m.String()])
+			seen[m.String()] = true
+		}
+	})
+
+	t.Run("have no product relations", func(t *testing.T) {
+		assert.False(t, testHasProductRelation(mentalPokerCards))
+	})
+
+	t.Run("rejects values which are not cards", func(t *testing.T) {
+		notACard := Card{Type: CardType(42), Suit: SuitClubs}
+		assert.Nil(t, cardElement(notACard))
+		key, err := GenerateMentalPokerKey()
+		assert.Nil(t, err)
+		_, err = key.ProveCard(MentalPokerDeck()[0], notACard)
+		assert.Equal(t, ErrNotACard, err)
+	})
+}
+
+func TestMentalPokerKey(t *testing.T) {
+	keys := testMentalPokerKeys(t)
+
+	t.Run("parses its own bytes", func(t *testing.T) {
+		key, err := ParseMentalPokerKey(keys[0].Bytes())
+		assert.Nil(t, err)
+		assert.True(t, key.Matches(keys[0].Public()))
+		assert.False(t, key.Matches(keys[1].Public()))
+	})
+
+	t.Run("rejects invalid keys", func(t *testing.T) {
+		_, err := ParseMentalPokerKey([]byte{1, 2, 3})
+		assert.Equal(t, ErrInvalidKey, err)
+		_, err = ParseMentalPokerKey(make([]byte, MentalPokerElementSize))
+		assert.Equal(t, ErrInvalidKey, err)
+	})
+}
+
+func TestMentalPokerDeck(t *testing.T) {
+	keys := testMentalPokerKeys(t)
+	deck := MentalPokerDeck()
+	var err error
+	for _, key := range keys {
+		shuffled, err := key.ShuffleDeck(deck)
+		assert.Nil(t, err)
+		assert.True(t, key.VerifyShuffle(deck, shuffled))
+		deck = shuffled
+	}
+
+	t.Run("decrypts once the other keys are stripped", func(t *testing.T) {
+		seen := make(map[Card]bool)
+		for _, value := range deck {
+			for _, key := range keys[1:] {
+				stripped, proof, err := key.Strip(value)
+				assert.Nil(t, err)
+				assert.Nil(t, VerifyStrip(key.Public(), value, stripped, proof))
+				value = stripped
+			}
+			card, err := keys[0].Decrypt(value)
+			assert.Nil(t, err)
+			seen[card] = true
+
+			proof, err := keys[0].ProveCard(value, card)
+			assert.Nil(t, err)
+			assert.Nil(t, VerifyCard(keys[0].Public(), value, card, proof))
+		}
+		assert.Equal(t, 32, len(seen))
+	})
+
+	t.Run("does not decrypt with other layers left", func(t *testing.T) {
+		_, err = keys[0].Decrypt(deck[0])
+		assert.Equal(t, ErrNotACard, err)
+	})
+
+	t.Run("rejects a strip with the wrong key", func(t *testing.T) {
+		stripped, proof, err := keys[1].Strip(deck[0])
+		assert.Nil(t, err)
+		assert.Equal(t, ErrInvalidProof, VerifyStrip(keys[2].Public(), deck[0], stripped, proof))
+	})
+
+	t.Run("rejects a tampered strip", func(t *testing.T) {
+		stripped, proof, err := keys[1].Strip(deck[0])
+		assert.Nil(t, err)
+		other, _, err := keys[1].Strip(deck[1])
+		assert.Nil(t, err)
+		assert.Equal(t, ErrInvalidProof, VerifyStrip(keys[1].Public(), deck[0], other, proof))
+		assert.Nil(t, VerifyStrip(keys[1].Public(), deck[0], stripped, proof))
+	})
+
+	t.Run("rejects a lie about the card", func(t *testing.T) {
+		value := deck[0]
+		for _, key := range keys[1:] {
+			value, _, err = key.Strip(value)
+			assert.Nil(t, err)
+		}
+		card, err := keys[0].Decrypt(value)
+		assert.Nil(t, err)
+		proof, err := keys[0].ProveCard(value, card)
+		assert.Nil(t, err)
+		lie := Card{Suit: SuitDiamonds, Type: Card7}
+		if card == lie {
+			lie = Card{Suit: SuitDiamonds, Type: Card8}
+		}
+		assert.Equal(t, ErrInvalidProof, VerifyCard(keys[0].Public(), value, lie, proof))
+	})
+
+	t.Run("detects a substituted card in a shuffle", func(t *testing.T) {
+		input := MentalPokerDeck()
+		output, err := keys[0].ShuffleDeck(input)
+		assert.Nil(t, err)
+		output[3] = output[4]
+		assert.False(t, keys[0].VerifyShuffle(input, output))
+	})
+
+	t.Run("detects a shuffle with another key", func(t *testing.T) {
+		input := MentalPokerDeck()
+		output, err := keys[1].ShuffleDeck(input)
+		assert.Nil(t, err)
+		assert.False(t, keys[0].VerifyShuffle(input, output))
+	})
+}
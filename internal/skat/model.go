@@ -23,6 +23,9 @@ const (
 	GameTypeGrand    GameType = 5
 	GameTypeNull     GameType = 6
 	GameTypeJunk     GameType = 7
+	// Null Ouvert Hand in which the defenders may exchange cards before
+	// the first trick
+	GameTypeRevolution GameType = 8
 )
 
 func (t GameType) Pretty() string {
@@ -39,11 +42,21 @@ func (t GameType) Pretty() string {
 		return "Grand"
 	case GameTypeNull:
 		return "Null"
+	case GameTypeJunk:
+		return "Junk"
+	case GameTypeRevolution:
+		return "Revolution"
 	default:
 		return ""
 	}
 }
 
+// Test whether the game is played by the Null rules, i.e. without trumps and
+// the declarer must not take any trick
+func (t GameType) IsNull() bool {
+	return t == GameTypeNull || t == GameTypeRevolution
+}
+
 var (
 	StandardGameTypes = []GameType{GameTypeDiamonds, GameTypeHearts, GameTypeSpades, GameTypeClubs, GameTypeGrand, GameTypeNull}
 	SuitGameTypes     = []GameType{GameTypeDiamonds, GameTypeHearts, GameTypeSpades, GameTypeClubs}
@@ -59,9 +72,19 @@ const (
 	GameModifierSchneiderAnnounced GameModifier = 1 << 3
 	GameModifierSchwarzAnnounced   GameModifier = 1 << 4
 	GameModifierOuvert             GameModifier = 1 << 5
+	GameModifierJungfrau           GameModifier = 1 << 6
+	GameModifierDurchmarsch        GameModifier = 1 << 7
+	GameModifierKontra             GameModifier = 1 << 8
+	GameModifierRe                 GameModifier = 1 << 9
 
 	StateModifiers        GameModifier = GameModifierHand | GameModifierSchneider | GameModifierSchwarz
 	AnnouncementModifiers GameModifier = GameModifierSchneiderAnnounced | GameModifierSchwarzAnnounced | GameModifierOuvert
+	// Only used in Junk games: Jungfrau is set if at least one player did
+	// not take a single trick, Durchmarsch if one player took all tricks.
+	JunkModifiers GameModifier = GameModifierJungfrau | GameModifierDurchmarsch
+	// Given by the defenders (Kontra) and the declarer (Re) during play; each
+	// doubles the game value.
+	DoublingModifiers GameModifier = GameModifierKontra | GameModifierRe
 )
 
 func (m GameModifier) Pretty() string {
@@ -84,6 +107,18 @@ func (m GameModifier) Pretty() string {
 	if m.Test(GameModifierOuvert) {
 		parts = append(parts, "Ouvert")
 	}
+	if m.Test(GameModifierJungfrau) {
+		parts = append(parts, "Jungfrau")
+	}
+	if m.Test(GameModifierDurchmarsch) {
+		parts = append(parts, "Durchmarsch")
+	}
+	if m.Test(GameModifierKontra) {
+		parts = append(parts, "Kontra")
+	}
+	if m.Test(GameModifierRe) {
+		parts = append(parts, "Re")
+	}
 	return strings.Join(parts, ", ")
 }
 
@@ -215,7 +250,7 @@ func (c Card) Pretty() string {
 
 func (c Card) EffectiveSuit(gameType GameType) EffectiveSuit {
 	switch gameType {
-	case GameTypeGrand:
+	case GameTypeGrand, GameTypeJunk:
 		if c.Type == CardJack {
 			return EffectiveSuitTrumps
 		}
@@ -241,12 +276,12 @@ func (c Card) EffectiveSuit(gameType GameType) EffectiveSuit {
 
 func (c Card) RelativePower(gameType GameType) int {
 	switch gameType {
-	case GameTypeGrand:
+	case GameTypeGrand, GameTypeJunk:
 		if c.Type == CardJack {
 			return int(c.Suit)
 		}
 		return int(c.Type)
-	case GameTypeNull:
+	case GameTypeNull, GameTypeRevolution:
 		switch c.Type {
 		case Card10:
 			return 3
@@ -330,6 +365,18 @@ func (m GameModifier) Without(other GameModifier) GameModifier {
 	return m &^ other
 }
 
+// Return the factor by which Kontra and Re multiply the game value
+func (m GameModifier) DoublingFactor() int {
+	factor := 1
+	if m.Test(GameModifierKontra) {
+		factor = factor * 2
+	}
+	if m.Test(GameModifierRe) {
+		factor = factor * 2
+	}
+	return factor
+}
+
 func (m GameModifier) IsAnnounceable() bool {
 	return m.Normalized() == m && m&^AnnouncementModifiers == 0
 }
@@ -346,29 +393,18 @@ func (modifiers GameModifier) Normalized() GameModifier {
 	return result
 }
 
-// Test whether the given modifier set is valid for an announcement
+// Include modifiers implied by the announcement for the given game
+//
+// Under the standard rules, Ouvert implies Schneider and Schwarz announced in
+// suit games and Grand.
+func (modifiers GameModifier) NormalizedForGame(game GameType) GameModifier {
+	return standardRules.NormalizedModifiers(modifiers, game)
+}
+
+// Test whether the given modifier set is valid for an announcement under the
+// standard rules
 func (modifiers GameModifier) ValidForGame(game GameType) bool {
-	if modifiers != modifiers.Normalized() {
-		return false
-	}
-	switch game {
-	case GameTypeNull:
-		{
-			if modifiers.Test(GameModifierSchneiderAnnounced) || modifiers.Test(GameModifierSchwarzAnnounced) {
-				return false
-			}
-			return true
-		}
-	// Suit games + Grand
-	case GameTypeClubs, GameTypeDiamonds, GameTypeHearts, GameTypeSpades, GameTypeGrand:
-		{
-			if modifiers.Test(GameModifierSchneiderAnnounced) && !modifiers.Test(GameModifierHand) {
-				return false
-			}
-			return true
-		}
-	}
-	return false
+	return standardRules.ValidModifiers(modifiers, game)
 }
 
 func (cs CardSet) Contains(c Card) bool {
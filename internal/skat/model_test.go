@@ -61,6 +61,15 @@ func TestGameModifier(t *testing.T) {
 		assert.False(t, GameModifierSchwarzAnnounced.IsAnnounceable())
 		assert.False(t, GameModifierHand.IsAnnounceable())
 		assert.False(t, (GameModifierHand | GameModifierSchneiderAnnounced).IsAnnounceable())
+		assert.False(t, GameModifierKontra.IsAnnounceable())
+		assert.False(t, GameModifierRe.IsAnnounceable())
+	})
+
+	t.Run("DoublingFactor", func(t *testing.T) {
+		assert.Equal(t, 1, NoGameModifiers.DoublingFactor())
+		assert.Equal(t, 1, AnnouncementModifiers.DoublingFactor())
+		assert.Equal(t, 2, GameModifierKontra.DoublingFactor())
+		assert.Equal(t, 4, (GameModifierKontra | GameModifierRe).DoublingFactor())
 	})
 }
 
@@ -164,6 +173,30 @@ func TestGameModifierValidForGame(t *testing.T) {
 	})
 }
 
+func TestGameModifierNormalizedForGame(t *testing.T) {
+	suitGames := []GameType{GameTypeClubs, GameTypeDiamonds, GameTypeHearts, GameTypeSpades, GameTypeGrand}
+
+	t.Run("Ouvert implies Schneider and Schwarz announced for suit games", func(t *testing.T) {
+		for _, game := range suitGames {
+			assert.Equal(t, GameModifierHand|GameModifierOuvert|GameModifierSchneiderAnnounced|GameModifierSchwarzAnnounced, (GameModifierHand | GameModifierOuvert).NormalizedForGame(game))
+		}
+	})
+
+	t.Run("Ouvert without Hand is invalid for suit games", func(t *testing.T) {
+		for _, game := range suitGames {
+			assert.False(t, GameModifierOuvert.NormalizedForGame(game).ValidForGame(game))
+		}
+	})
+
+	t.Run("Ouvert implies nothing for null game", func(t *testing.T) {
+		assert.Equal(t, GameModifierOuvert, GameModifierOuvert.NormalizedForGame(GameTypeNull))
+	})
+
+	t.Run("includes implicit modifiers", func(t *testing.T) {
+		assert.Equal(t, GameModifierSchneider|GameModifierSchwarz, GameModifierSchwarz.NormalizedForGame(GameTypeHearts))
+	})
+}
+
 func TestCardOperations(t *testing.T) {
 	t.Run("contains", func(t *testing.T) {
 		cards := make(CardSet, 0)
@@ -243,6 +276,20 @@ func TestCardEffectiveSuit(t *testing.T) {
 		}
 	})
 
+	t.Run("junk leaves suits intact but maps jacks to trump", func(t *testing.T) {
+		for _, c := range CardTypes {
+			if c == CardJack {
+				for _, suit := range Suits {
+					assert.Equal(t, EffectiveSuitTrumps, c.As(suit).EffectiveSuit(GameTypeJunk))
+				}
+			} else {
+				for suit, effective := range suitMap {
+					assert.Equal(t, effective, c.As(suit).EffectiveSuit(GameTypeJunk))
+				}
+			}
+		}
+	})
+
 	t.Run("null maps all cards to their base effective suit", func(t *testing.T) {
 		for _, c := range CardTypes {
 			for suit, effective := range suitMap {
@@ -290,6 +337,18 @@ func TestCardRelativePower(t *testing.T) {
 		}
 	})
 
+	t.Run("junk jacks", func(t *testing.T) {
+		prevSuit := Suits[0]
+		for _, suit := range Suits[1:] {
+			assert.Less(
+				t,
+				CardJack.As(prevSuit).RelativePower(GameTypeJunk),
+				CardJack.As(suit).RelativePower(GameTypeJunk),
+			)
+			prevSuit = suit
+		}
+	})
+
 	t.Run("null", func(t *testing.T) {
 		nullCardOrder := []CardType{
 			Card7, Card8, Card9, Card10, CardJack, CardQueen, CardKing,
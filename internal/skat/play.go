@@ -11,6 +11,18 @@ var (
 type PlayingPlayerState struct {
 	Hand     CardSet
 	WonCards CardSet
+	Tricks   int
+}
+
+// A completed trick
+type TrickRecord struct {
+	// Player who led the trick
+	Forehand int `json:"forehand"`
+	// Cards in the order they were played
+	Cards Trick `json:"cards"`
+	// Player who played the respective card
+	Players [3]int `json:"players"`
+	Winner  int    `json:"winner"`
 }
 
 type PlayingState struct {
@@ -21,7 +33,10 @@ type PlayingState struct {
 	lastTrick       Trick
 	lastTrickWinner int
 	table           CardSet
+	history         []TrickRecord
 	players         [3]PlayingPlayerState
+	// In Junk games, the skat goes to the taker of the last trick
+	skat CardSet
 }
 
 func NewPlayingState(declarer int, gameType GameType, hands [3]*CardSet, pushedCards CardSet) *PlayingState {
@@ -31,6 +46,7 @@ func NewPlayingState(declarer int, gameType GameType, hands [3]*CardSet, pushedC
 		declarer:        declarer,
 		gameType:        gameType,
 		lastTrickWinner: PlayerNone,
+		history:         make([]TrickRecord, 0, 10),
 		players: [3]PlayingPlayerState{
 			PlayingPlayerState{
 				Hand:     hands[0].Copy(),
@@ -52,6 +68,16 @@ func NewPlayingState(declarer int, gameType GameType, hands [3]*CardSet, pushedC
 	return result
 }
 
+// Create a playing state for a Junk game
+//
+// There is no declarer in Junk games; the skat is handed to whoever takes the
+// last trick.
+func NewJunkPlayingState(hands [3]*CardSet, skat CardSet) *PlayingState {
+	result := NewPlayingState(PlayerNone, GameTypeJunk, hands, nil)
+	result.skat = skat.Copy()
+	return result
+}
+
 func (s *PlayingState) Declarer() int {
 	return s.declarer
 }
@@ -64,14 +90,98 @@ func (s *PlayingState) GetLastTrick() (Trick, int) {
 	return s.lastTrick.Copy(), s.lastTrickWinner
 }
 
+// Return all completed tricks in the order they were played
+func (s *PlayingState) GetTrickHistory() []TrickRecord {
+	result := make([]TrickRecord, len(s.history))
+	copy(result, s.history)
+	return result
+}
+
+// Return the player who leads the current trick
+func (s *PlayingState) GetForehand() int {
+	return s.forehand
+}
+
 func (s *PlayingState) GetWonCards(player int) CardSet {
 	return s.players[player].WonCards.Copy()
 }
 
+// Return the number of tricks the player has taken so far
+func (s *PlayingState) GetTrickCount(player int) int {
+	return s.players[player].Tricks
+}
+
+// Return true if the declarer has taken at least one trick
+func (s *PlayingState) DeclarerTookTrick() bool {
+	if s.declarer == PlayerNone {
+		return false
+	}
+	return s.players[s.declarer].Tricks > 0
+}
+
+// Return true if any defender has taken at least one trick
+func (s *PlayingState) DefendersTookTrick() bool {
+	for i := range s.players {
+		if i != s.declarer && s.players[i].Tricks > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Hand all cards which have not been taken yet to the given player
+//
+// This ends the game: all hands and the table are emptied, and the remaining
+// tricks are counted for the given player.
+func (s *PlayingState) AwardRemainingCards(player int) {
+	remaining := s.table.Copy()
+	s.table = s.table[:0]
+	for i := range s.players {
+		remaining = append(remaining, s.players[i].Hand...)
+		s.players[i].Hand = s.players[i].Hand[:0]
+	}
+	s.players[player].WonCards = append(s.players[player].WonCards, remaining...)
+	s.players[player].Tricks = s.players[player].Tricks + (len(remaining)+2)/3
+	if len(s.skat) > 0 {
+		s.players[player].WonCards = append(s.players[player].WonCards, s.skat...)
+		s.skat = nil
+	}
+}
+
 func (s *PlayingState) GetCurrentPlayer() int {
 	return s.current
 }
 
+// Return the cards of the hand which may be played onto the table
+func legalCards(hand CardSet, table CardSet, gameType GameType) CardSet {
+	if len(table) == 0 {
+		return hand.Copy()
+	}
+	tableSuit := table[0].EffectiveSuit(gameType)
+	result := make(CardSet, 0, len(hand))
+	for _, card := range hand {
+		if card.EffectiveSuit(gameType) == tableSuit {
+			result = append(result, card)
+		}
+	}
+	if len(result) == 0 {
+		return hand.Copy()
+	}
+	return result
+}
+
+// Return the cards the player may play
+//
+// The player has to follow the effective suit of the first card on the table,
+// i.e. trumps if a jack or a card of the trump suit was led. Returns nil if it
+// is not the turn of the player.
+func (s *PlayingState) LegalCards(player int) CardSet {
+	if player != s.current {
+		return nil
+	}
+	return legalCards(s.players[player].Hand, s.table, s.gameType)
+}
+
 func (s *PlayingState) tableSuit() EffectiveSuit {
 	return s.table[0].EffectiveSuit(s.gameType)
 }
@@ -128,8 +238,23 @@ func (s *PlayingState) relativeToAbsolutePlayer(relativePlayer int) int {
 func (s *PlayingState) concludeTrick() {
 	s.lastTrick = Trick{s.table[0], s.table[1], s.table[2]}
 	s.lastTrickWinner = s.relativeToAbsolutePlayer(s.lastTrick.Taker(s.gameType))
+	s.history = append(s.history, TrickRecord{
+		Forehand: s.forehand,
+		Cards:    s.lastTrick,
+		Players: [3]int{
+			s.relativeToAbsolutePlayer(0),
+			s.relativeToAbsolutePlayer(1),
+			s.relativeToAbsolutePlayer(2),
+		},
+		Winner: s.lastTrickWinner,
+	})
 	s.players[s.lastTrickWinner].WonCards = append(s.players[s.lastTrickWinner].WonCards, s.table...)
+	s.players[s.lastTrickWinner].Tricks = s.players[s.lastTrickWinner].Tricks + 1
 	s.table = s.table[:0]
+	if len(s.skat) > 0 && len(s.players[s.lastTrickWinner].Hand) == 0 {
+		s.players[s.lastTrickWinner].WonCards = append(s.players[s.lastTrickWinner].WonCards, s.skat...)
+		s.skat = nil
+	}
 	s.current = s.lastTrickWinner
 	s.forehand = s.lastTrickWinner
 }
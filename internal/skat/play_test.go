@@ -91,6 +91,42 @@ func testPlayingState(t *testing.T) *playingStateTest {
 	return result
 }
 
+func testJunkPlayingState(t *testing.T) *playingStateTest {
+	result := testPlayingState(t)
+	result.s = NewJunkPlayingState(
+		[3]*CardSet{
+			&result.handsBuf[0],
+			&result.handsBuf[1],
+			&result.handsBuf[2],
+		},
+		CardSet{Card7.As(SuitDiamonds), Card8.As(SuitDiamonds)},
+	)
+	return result
+}
+
+// Play the first legal card of the current player until n cards have been
+// played or no cards are left
+//
+// The cards are played with play, e.g. PlayingState.Play or
+// GameState.PlayCard. With n < 0, the game is played out. Returns the number
+// of cards played.
+func testPlayOut(t *testing.T, s *PlayingState, play func(player int, card Card) error, n int) int {
+	played := 0
+	for played != n && len(s.GetHand(s.GetCurrentPlayer())) > 0 {
+		player := s.GetCurrentPlayer()
+		success := false
+		for _, card := range s.GetHand(player) {
+			if play(player, card) == nil {
+				success = true
+				break
+			}
+		}
+		assert.True(t, success)
+		played = played + 1
+	}
+	return played
+}
+
 func TestPlayingStateInit(t *testing.T) {
 	t.Run("last trick is undefined", func(t *testing.T) {
 		ts := testPlayingState(t)
@@ -254,3 +290,167 @@ func TestPlayTrick(t *testing.T) {
 		assert.Equal(t, PlayerInitialRearhand, ts.s.GetCurrentPlayer())
 	})
 }
+
+func TestTrickHistory(t *testing.T) {
+	t.Run("starts empty", func(t *testing.T) {
+		ts := testPlayingState(t)
+		assert.Equal(t, []TrickRecord{}, ts.s.GetTrickHistory())
+		assert.Equal(t, PlayerInitialForehand, ts.s.GetForehand())
+	})
+
+	t.Run("records tricks in order", func(t *testing.T) {
+		ts := testPlayingState(t)
+		assert.Nil(t, ts.s.Play(PlayerInitialForehand, Card7.As(SuitHearts)))
+		assert.Nil(t, ts.s.Play(PlayerInitialMiddlehand, Card8.As(SuitHearts)))
+		assert.Equal(t, 0, len(ts.s.GetTrickHistory()))
+		assert.Nil(t, ts.s.Play(PlayerInitialRearhand, Card9.As(SuitHearts)))
+		assert.Equal(t, PlayerInitialRearhand, ts.s.GetForehand())
+
+		assert.Nil(t, ts.s.Play(PlayerInitialRearhand, Card10.As(SuitClubs)))
+		assert.Nil(t, ts.s.Play(PlayerInitialForehand, Card8.As(SuitClubs)))
+		assert.Nil(t, ts.s.Play(PlayerInitialMiddlehand, CardAce.As(SuitClubs)))
+
+		assert.Equal(t, []TrickRecord{
+			TrickRecord{
+				Forehand: PlayerInitialForehand,
+				Cards:    Trick{Card7.As(SuitHearts), Card8.As(SuitHearts), Card9.As(SuitHearts)},
+				Players:  [3]int{PlayerInitialForehand, PlayerInitialMiddlehand, PlayerInitialRearhand},
+				Winner:   PlayerInitialRearhand,
+			},
+			TrickRecord{
+				Forehand: PlayerInitialRearhand,
+				Cards:    Trick{Card10.As(SuitClubs), Card8.As(SuitClubs), CardAce.As(SuitClubs)},
+				Players:  [3]int{PlayerInitialRearhand, PlayerInitialForehand, PlayerInitialMiddlehand},
+				Winner:   PlayerInitialMiddlehand,
+			},
+		}, ts.s.GetTrickHistory())
+		assert.Equal(t, PlayerInitialMiddlehand, ts.s.GetForehand())
+	})
+
+	t.Run("full game has ten tricks", func(t *testing.T) {
+		ts := testPlayingState(t)
+		testPlayOut(t, ts.s, ts.s.Play, -1)
+		assert.Equal(t, 10, len(ts.s.GetTrickHistory()))
+	})
+}
+
+func TestPlayJunk(t *testing.T) {
+	t.Run("nobody owns the skat initially", func(t *testing.T) {
+		ts := testJunkPlayingState(t)
+		assert.Equal(t, PlayerNone, ts.s.Declarer())
+		assert.Equal(t, GameTypeJunk, ts.s.GameType())
+		for i := range ts.handsBuf {
+			assert.Equal(t, CardSet{}, ts.s.GetWonCards(i))
+		}
+	})
+
+	t.Run("jacks are trumps", func(t *testing.T) {
+		ts := testJunkPlayingState(t)
+		assert.Nil(t, ts.s.Play(PlayerInitialForehand, CardJack.As(SuitSpades)))
+		assert.Equal(t, ErrMustFollowSuit, ts.s.Play(PlayerInitialMiddlehand, Card8.As(SuitHearts)))
+		assert.Nil(t, ts.s.Play(PlayerInitialMiddlehand, CardJack.As(SuitDiamonds)))
+		assert.Nil(t, ts.s.Play(PlayerInitialRearhand, CardJack.As(SuitHearts)))
+		_, taker := ts.s.GetLastTrick()
+		assert.Equal(t, PlayerInitialForehand, taker)
+		assert.Equal(t, 1, ts.s.GetTrickCount(PlayerInitialForehand))
+		assert.Equal(t, 0, ts.s.GetTrickCount(PlayerInitialRearhand))
+	})
+
+	t.Run("last trick takes the skat", func(t *testing.T) {
+		ts := testJunkPlayingState(t)
+		testPlayOut(t, ts.s, ts.s.Play, -1)
+		_, taker := ts.s.GetLastTrick()
+		assert.Equal(t, 2, len(ts.s.GetWonCards(taker))-3*ts.s.GetTrickCount(taker))
+		assert.True(t, ts.s.GetWonCards(taker).Contains(Card7.As(SuitDiamonds)))
+		assert.True(t, ts.s.GetWonCards(taker).Contains(Card8.As(SuitDiamonds)))
+		total := 0
+		for i := range ts.handsBuf {
+			total = total + ts.s.GetWonCards(i).Value()
+		}
+		assert.Equal(t, 120, total)
+	})
+}
+
+func TestAwardRemainingCards(t *testing.T) {
+	t.Run("moves hands and table to the player", func(t *testing.T) {
+		s := testPlayingState(t).s
+		player := s.GetCurrentPlayer()
+		assert.Nil(t, s.Play(player, s.GetHand(player)[0]))
+		assert.False(t, s.DeclarerTookTrick())
+		assert.False(t, s.DefendersTookTrick())
+
+		s.AwardRemainingCards(PlayerInitialRearhand)
+		for i := 0; i < 3; i = i + 1 {
+			assert.Equal(t, 0, len(s.GetHand(i)))
+		}
+		assert.Equal(t, 0, len(s.GetTable()))
+		assert.Equal(t, 10, s.GetTrickCount(PlayerInitialRearhand))
+		assert.Equal(t, 30, len(s.GetWonCards(PlayerInitialRearhand)))
+	})
+}
+
+func TestPlayingStateLegalCards(t *testing.T) {
+	newState := func(gameType GameType) *PlayingState {
+		pst := testPlayingState(t)
+		return NewPlayingState(
+			PlayerInitialForehand,
+			gameType,
+			[3]*CardSet{
+				&pst.handsBuf[0],
+				&pst.handsBuf[1],
+				&pst.handsBuf[2],
+			},
+			nil,
+		)
+	}
+
+	t.Run("any card may be led", func(t *testing.T) {
+		s := newState(GameTypeHearts)
+		assert.Equal(t, s.GetHand(PlayerInitialForehand), s.LegalCards(PlayerInitialForehand))
+	})
+
+	t.Run("only the player on turn has legal cards", func(t *testing.T) {
+		s := newState(GameTypeHearts)
+		assert.Nil(t, s.LegalCards(PlayerInitialMiddlehand))
+		assert.Nil(t, s.LegalCards(PlayerInitialRearhand))
+	})
+
+	t.Run("jacks are trumps in suit games", func(t *testing.T) {
+		s := newState(GameTypeHearts)
+		assert.Nil(t, s.Play(PlayerInitialForehand, CardAce.As(SuitHearts)))
+		assert.Equal(t, CardSet{CardJack.As(SuitDiamonds), Card8.As(SuitHearts)}, s.LegalCards(PlayerInitialMiddlehand))
+	})
+
+	t.Run("only jacks are trumps in grand", func(t *testing.T) {
+		s := newState(GameTypeGrand)
+		assert.Nil(t, s.Play(PlayerInitialForehand, CardJack.As(SuitClubs)))
+		assert.Equal(t, CardSet{CardJack.As(SuitDiamonds)}, s.LegalCards(PlayerInitialMiddlehand))
+	})
+
+	t.Run("jacks belong to their suit in null", func(t *testing.T) {
+		s := newState(GameTypeNull)
+		assert.Nil(t, s.Play(PlayerInitialForehand, CardJack.As(SuitClubs)))
+		legal := s.LegalCards(PlayerInitialMiddlehand)
+		assert.Equal(t, 5, len(legal))
+		for _, card := range legal {
+			assert.Equal(t, SuitClubs, card.Suit)
+		}
+	})
+
+	t.Run("any card if the suit cannot be followed", func(t *testing.T) {
+		s := newState(GameTypeDiamonds)
+		assert.Nil(t, s.Play(PlayerInitialForehand, Card10.As(SuitSpades)))
+		assert.Equal(t, s.GetHand(PlayerInitialMiddlehand), s.LegalCards(PlayerInitialMiddlehand))
+	})
+
+	t.Run("play rejects other cards", func(t *testing.T) {
+		s := newState(GameTypeHearts)
+		assert.Nil(t, s.Play(PlayerInitialForehand, CardAce.As(SuitHearts)))
+		legal := s.LegalCards(PlayerInitialMiddlehand)
+		for _, card := range s.GetHand(PlayerInitialMiddlehand) {
+			if !legal.Contains(card) {
+				assert.Equal(t, ErrMustFollowSuit, s.Play(PlayerInitialMiddlehand, card))
+			}
+		}
+	})
+}
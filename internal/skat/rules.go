@@ -0,0 +1,301 @@
+package skat
+
+import (
+	"errors"
+	"sort"
+)
+
+var (
+	ErrInvalidRuleSet = errors.New("invalid rule set")
+	ErrRuleDisabled   = errors.New("not allowed by the house rules")
+
+	standardRules = StandardRuleSet()
+)
+
+// What happens when all players pass during bidding
+type AllPassRule string
+
+const (
+	// Play a Junk game (Ramsch)
+	AllPassJunk AllPassRule = "junk"
+
+	// End the game without a score and deal again
+	AllPassRedeal AllPassRule = "redeal"
+)
+
+// How the cards are dealt, see DealingStrategy
+type DealingMode string
+
+const (
+	// The server shuffles with the seeds of all parties and knows all hands
+	DealingSeeded DealingMode = "seeded"
+
+	// The players shuffle and deal encrypted cards among themselves; the
+	// server only learns the cards as they are played
+	DealingMentalPoker DealingMode = "mental_poker"
+)
+
+// The values of the four Null games
+type NullValues struct {
+	Plain      int `json:"plain"`
+	Hand       int `json:"hand"`
+	Ouvert     int `json:"ouvert"`
+	HandOuvert int `json:"handOuvert"`
+	Revolution int `json:"revolution"`
+}
+
+// House rules of a table
+type RuleSet struct {
+	// Base value of a Grand, either 24 or 20
+	GrandBase  int        `json:"grandBase"`
+	NullValues NullValues `json:"nullValues"`
+	// Ouvert in a suit game or Grand includes Schneider and Schwarz
+	// announced
+	OuvertImpliesSchwarz bool `json:"ouvertImpliesSchwarz"`
+	// Schneider, Schwarz and Ouvert may only be announced in Hand games;
+	// does not apply to Null
+	AnnouncementsRequireHand bool        `json:"announcementsRequireHand"`
+	AllPass                  AllPassRule `json:"allPass"`
+	// Junk games are played as Schieberamsch: before the first card, each
+	// player may take the skat and push two cards on, doubling the result
+	Schieberamsch bool `json:"schieberamsch"`
+	// Revolution may be declared, see GameTypeRevolution
+	Revolution bool       `json:"revolution"`
+	KontraRe   bool       `json:"kontraRe"`
+	Bock       BockConfig `json:"bock"`
+	// Empty for DealingSeeded. Revolution and Schieberamsch cannot be played
+	// with DealingMentalPoker.
+	Dealing DealingMode `json:"dealing,omitempty"`
+}
+
+// Return the rules of the international Skat order, plus Junk games when
+// all players pass
+func StandardRuleSet() *RuleSet {
+	return &RuleSet{
+		GrandBase: 24,
+		NullValues: NullValues{
+			Plain:      23,
+			Hand:       35,
+			Ouvert:     46,
+			HandOuvert: 59,
+			Revolution: 92,
+		},
+		OuvertImpliesSchwarz:     true,
+		AnnouncementsRequireHand: true,
+		AllPass:                  AllPassJunk,
+		KontraRe:                 true,
+		Bock:                     DefaultBockConfig(),
+		Dealing:                  DealingSeeded,
+	}
+}
+
+func (r *RuleSet) Validate() error {
+	if r.GrandBase != 20 && r.GrandBase != 24 {
+		return ErrInvalidRuleSet
+	}
+	nv := r.NullValues
+	if nv.Plain <= 0 || nv.Hand <= 0 || nv.Ouvert <= 0 || nv.HandOuvert <= 0 {
+		return ErrInvalidRuleSet
+	}
+	if r.Revolution && nv.Revolution <= 0 {
+		return ErrInvalidRuleSet
+	}
+	switch r.AllPass {
+	case AllPassJunk, AllPassRedeal:
+	default:
+		return ErrInvalidRuleSet
+	}
+	if r.Bock.Enabled && (r.Bock.RoundLength < 1 || r.Bock.Multiplier < 1) {
+		return ErrInvalidRuleSet
+	}
+	switch r.Dealing {
+	case "", DealingSeeded:
+	case DealingMentalPoker:
+		// both need the cards of several players at once
+		if r.Revolution || r.Schieberamsch {
+			return ErrInvalidRuleSet
+		}
+	default:
+		return ErrInvalidRuleSet
+	}
+	return nil
+}
+
+// Return the base value of a suit game or grand
+//
+// Returns 0 for all other game types.
+func (r *RuleSet) BaseValue(gameType GameType) int {
+	switch gameType {
+	case GameTypeDiamonds:
+		return 9
+	case GameTypeHearts:
+		return 10
+	case GameTypeSpades:
+		return 11
+	case GameTypeClubs:
+		return 12
+	case GameTypeGrand:
+		return r.GrandBase
+	}
+	return 0
+}
+
+func (r *RuleSet) GameValue(initialDeclarerHand CardSet, gameType GameType, modifiers GameModifier) (base int, factor int) {
+	factor = 1
+	switch gameType {
+	case GameTypeNull:
+		isHand := modifiers.Test(GameModifierHand)
+		isOuvert := modifiers.Test(GameModifierOuvert)
+		if isHand {
+			if isOuvert {
+				return r.NullValues.HandOuvert, factor
+			} else {
+				return r.NullValues.Hand, factor
+			}
+		} else {
+			if isOuvert {
+				return r.NullValues.Ouvert, factor
+			} else {
+				return r.NullValues.Plain, factor
+			}
+		}
+	case GameTypeRevolution:
+		return r.NullValues.Revolution, factor
+	case GameTypeDiamonds, GameTypeHearts, GameTypeSpades, GameTypeClubs, GameTypeGrand:
+		base = r.BaseValue(gameType)
+		factor = 1 + initialDeclarerHand.GetMatadorsJackStrength(gameType)
+		isHand := modifiers.Test(GameModifierHand)
+		if isHand {
+			factor = factor + 1
+		}
+		if isHand || !r.AnnouncementsRequireHand {
+			if modifiers.Test(GameModifierSchneiderAnnounced) {
+				factor = factor + 1
+			}
+			if modifiers.Test(GameModifierSchwarzAnnounced) {
+				factor = factor + 1
+			}
+		}
+		if modifiers.Test(GameModifierSchneider) {
+			factor = factor + 1
+		}
+		if modifiers.Test(GameModifierSchwarz) {
+			factor = factor + 1
+		}
+		if modifiers.Test(GameModifierOuvert) {
+			factor = factor + 1
+		}
+		return base, factor
+	}
+	return base, factor
+}
+
+// Include modifiers implied by the announcement for the given game
+func (r *RuleSet) NormalizedModifiers(modifiers GameModifier, game GameType) GameModifier {
+	result := modifiers.Normalized()
+	switch game {
+	case GameTypeClubs, GameTypeDiamonds, GameTypeHearts, GameTypeSpades, GameTypeGrand:
+		if r.OuvertImpliesSchwarz && result.Test(GameModifierOuvert) {
+			result = result.With(GameModifierSchneiderAnnounced).With(GameModifierSchwarzAnnounced)
+		}
+	case GameTypeRevolution:
+		result = result.With(GameModifierOuvert)
+	}
+	return result
+}
+
+// Test whether the given modifier set is valid for an announcement
+func (r *RuleSet) ValidModifiers(modifiers GameModifier, game GameType) bool {
+	if modifiers != modifiers.Normalized() {
+		return false
+	}
+	switch game {
+	case GameTypeNull:
+		{
+			if modifiers.Test(GameModifierSchneiderAnnounced) || modifiers.Test(GameModifierSchwarzAnnounced) {
+				return false
+			}
+			return true
+		}
+	case GameTypeRevolution:
+		{
+			if !r.Revolution || modifiers != GameModifierHand|GameModifierOuvert {
+				return false
+			}
+			return true
+		}
+	// Suit games + Grand
+	case GameTypeClubs, GameTypeDiamonds, GameTypeHearts, GameTypeSpades, GameTypeGrand:
+		{
+			announced := modifiers.Test(GameModifierSchneiderAnnounced) || modifiers.Test(GameModifierOuvert)
+			if r.AnnouncementsRequireHand && announced && !modifiers.Test(GameModifierHand) {
+				return false
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Test whether Ouvert games can be played under these rules
+//
+// The hand of the declarer cannot be shown while the deal is sealed.
+func (r *RuleSet) OuvertAllowed() bool {
+	return r.Dealing != DealingMentalPoker
+}
+
+// Return all values which can be bid, in ascending order
+func (r *RuleSet) BidLadder() []int {
+	suitFactor, grandFactor := maxSuitFactor, maxGrandFactor
+	nullModifiers := []GameModifier{
+		NoGameModifiers,
+		GameModifierHand,
+		GameModifierOuvert,
+		GameModifierHand | GameModifierOuvert,
+	}
+	if !r.OuvertAllowed() {
+		suitFactor, grandFactor = suitFactor-1, grandFactor-1
+		nullModifiers = nullModifiers[:2]
+	}
+
+	values := make(map[int]bool)
+	for _, gameType := range SuitGameTypes {
+		base := r.BaseValue(gameType)
+		for factor := 2; factor <= suitFactor; factor = factor + 1 {
+			values[base*factor] = true
+		}
+	}
+	grandBase := r.BaseValue(GameTypeGrand)
+	for factor := 2; factor <= grandFactor; factor = factor + 1 {
+		values[grandBase*factor] = true
+	}
+	for _, modifiers := range nullModifiers {
+		value, _ := r.GameValue(nil, GameTypeNull, modifiers)
+		values[value] = true
+	}
+	if r.Revolution {
+		values[r.NullValues.Revolution] = true
+	}
+
+	result := make([]int, 0, len(values))
+	for value := range values {
+		if value >= MinimumBid {
+			result = append(result, value)
+		}
+	}
+	sort.Ints(result)
+	return result
+}
+
+// Test whether a value can be bid under these rules
+func (r *RuleSet) IsValidBid(value int) bool {
+	return isOnLadder(r.BidLadder(), value)
+}
+
+// Return the lowest value which can be bid under these rules and which is
+// higher than the given value
+//
+// Returns BidNone if there is no higher value.
+func (r *RuleSet) NextBid(value int) int {
+	return nextOnLadder(r.BidLadder(), value)
+}
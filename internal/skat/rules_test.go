@@ -0,0 +1,239 @@
+package skat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testGetRulesPlayingPhaseGame(t *testing.T, rules *RuleSet, gameType GameType, modifiers GameModifier) *GameState {
+	g := testNewDealtGame(t, rules)
+	testWinBidding(t, g)
+	assert.Nil(t, g.Declare(PlayerInitialMiddlehand, gameType, modifiers, nil))
+	return g
+}
+
+func TestRuleSetValidate(t *testing.T) {
+	t.Run("standard rules are valid", func(t *testing.T) {
+		assert.Nil(t, StandardRuleSet().Validate())
+	})
+
+	t.Run("grand base must be 20 or 24", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.GrandBase = 20
+		assert.Nil(t, rules.Validate())
+		rules.GrandBase = 23
+		assert.Equal(t, ErrInvalidRuleSet, rules.Validate())
+	})
+
+	t.Run("null values must be positive", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.NullValues.Ouvert = 0
+		assert.Equal(t, ErrInvalidRuleSet, rules.Validate())
+	})
+
+	t.Run("revolution needs a value", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.Revolution = true
+		assert.Nil(t, rules.Validate())
+		rules.NullValues.Revolution = 0
+		assert.Equal(t, ErrInvalidRuleSet, rules.Validate())
+	})
+
+	t.Run("all pass rule must be known", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.AllPass = AllPassRule("")
+		assert.Equal(t, ErrInvalidRuleSet, rules.Validate())
+	})
+
+	t.Run("dealing mode must be known", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.Dealing = ""
+		assert.Nil(t, rules.Validate())
+		rules.Dealing = DealingMentalPoker
+		assert.Nil(t, rules.Validate())
+		rules.Dealing = DealingMode("telepathy")
+		assert.Equal(t, ErrInvalidRuleSet, rules.Validate())
+	})
+
+	t.Run("mental poker rules out skat passing", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.Dealing = DealingMentalPoker
+		rules.Revolution = true
+		assert.Equal(t, ErrInvalidRuleSet, rules.Validate())
+		rules.Revolution = false
+		rules.Schieberamsch = true
+		assert.Equal(t, ErrInvalidRuleSet, rules.Validate())
+	})
+
+	t.Run("new game rejects invalid rules", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.GrandBase = 0
+		_, err := NewGame(false, LeagueScoreDefinition(), rules)
+		assert.Equal(t, ErrInvalidRuleSet, err)
+	})
+}
+
+func TestRuleSetGameValue(t *testing.T) {
+	t.Run("grand base", func(t *testing.T) {
+		rules := StandardRuleSet()
+		assert.Equal(t, 24, rules.BaseValue(GameTypeGrand))
+		rules.GrandBase = 20
+		assert.Equal(t, 20, rules.BaseValue(GameTypeGrand))
+		assert.Equal(t, 12, rules.BaseValue(GameTypeClubs))
+	})
+
+	t.Run("null values", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.NullValues = NullValues{Plain: 20, Hand: 30, Ouvert: 40, HandOuvert: 50}
+		for modifiers, expected := range map[GameModifier]int{
+			NoGameModifiers:                       20,
+			GameModifierHand:                      30,
+			GameModifierOuvert:                    40,
+			GameModifierHand | GameModifierOuvert: 50,
+		} {
+			base, factor := rules.GameValue(nil, GameTypeNull, modifiers)
+			assert.Equal(t, expected, base)
+			assert.Equal(t, 1, factor)
+		}
+	})
+
+	t.Run("revolution value", func(t *testing.T) {
+		base, factor := CalculateGameValue(nil, GameTypeRevolution, GameModifierHand|GameModifierOuvert)
+		assert.Equal(t, 92, base)
+		assert.Equal(t, 1, factor)
+	})
+
+	t.Run("standard rules match package functions", func(t *testing.T) {
+		hand := CardSet{CardJack.As(SuitClubs), CardJack.As(SuitSpades)}
+		for _, gameType := range StandardGameTypes {
+			base, factor := CalculateGameValue(hand, gameType, GameModifierHand)
+			rbase, rfactor := StandardRuleSet().GameValue(hand, gameType, GameModifierHand)
+			assert.Equal(t, base, rbase)
+			assert.Equal(t, factor, rfactor)
+		}
+	})
+
+	t.Run("announcements without hand", func(t *testing.T) {
+		hand := CardSet{CardJack.As(SuitClubs), CardJack.As(SuitSpades), Card7.As(SuitDiamonds)}
+		rules := StandardRuleSet()
+		_, factor := rules.GameValue(hand, GameTypeHearts, GameModifierSchneiderAnnounced)
+		assert.Equal(t, 3, factor)
+		rules.AnnouncementsRequireHand = false
+		_, factor = rules.GameValue(hand, GameTypeHearts, GameModifierSchneiderAnnounced)
+		assert.Equal(t, 4, factor)
+		_, factor = rules.GameValue(hand, GameTypeHearts, GameModifierSchneiderAnnounced|GameModifierSchwarzAnnounced)
+		assert.Equal(t, 5, factor)
+	})
+}
+
+func TestRuleSetModifiers(t *testing.T) {
+	t.Run("ouvert without schwarz", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.OuvertImpliesSchwarz = false
+		modifiers := GameModifierHand | GameModifierOuvert
+		assert.Equal(t, modifiers, rules.NormalizedModifiers(modifiers, GameTypeGrand))
+		assert.True(t, rules.ValidModifiers(modifiers, GameTypeGrand))
+	})
+
+	t.Run("announcements without hand", func(t *testing.T) {
+		rules := StandardRuleSet()
+		assert.False(t, rules.ValidModifiers(GameModifierSchneiderAnnounced, GameTypeHearts))
+		assert.False(t, rules.ValidModifiers(GameModifierOuvert, GameTypeHearts))
+		rules.AnnouncementsRequireHand = false
+		assert.True(t, rules.ValidModifiers(GameModifierSchneiderAnnounced, GameTypeHearts))
+		assert.True(t, rules.ValidModifiers(GameModifierSchneiderAnnounced|GameModifierSchwarzAnnounced, GameTypeHearts))
+	})
+
+	t.Run("null games never take schneider announcements", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.AnnouncementsRequireHand = false
+		assert.False(t, rules.ValidModifiers(GameModifierHand|GameModifierSchneiderAnnounced, GameTypeNull))
+	})
+
+	t.Run("revolution is always hand and ouvert", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.Revolution = true
+		assert.Equal(t, GameModifierHand|GameModifierOuvert, rules.NormalizedModifiers(GameModifierHand, GameTypeRevolution))
+		assert.True(t, rules.ValidModifiers(GameModifierHand|GameModifierOuvert, GameTypeRevolution))
+		assert.False(t, rules.ValidModifiers(GameModifierOuvert, GameTypeRevolution))
+		rules.Revolution = false
+		assert.False(t, rules.ValidModifiers(GameModifierHand|GameModifierOuvert, GameTypeRevolution))
+	})
+}
+
+func TestRuleSetBidLadder(t *testing.T) {
+	t.Run("standard ladder", func(t *testing.T) {
+		assert.Equal(t, BidLadder(), StandardRuleSet().BidLadder())
+	})
+
+	t.Run("grand base 20", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.GrandBase = 20
+		assert.True(t, rules.IsValidBid(220))
+		assert.False(t, rules.IsValidBid(264))
+		assert.Equal(t, 220, rules.NextBid(216))
+		assert.Equal(t, BidNone, rules.NextBid(220))
+	})
+
+	t.Run("null values", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.NullValues.Plain = 19
+		assert.True(t, rules.IsValidBid(19))
+		assert.False(t, rules.IsValidBid(23))
+
+		b := NewBiddingStateForRules(rules)
+		assert.Nil(t, b.Call(PlayerInitialMiddlehand, 18))
+		assert.Nil(t, b.Respond(PlayerInitialForehand, true))
+		assert.Equal(t, 19, b.NextLegalBid())
+		assert.Equal(t, ErrInvalidBid, b.Call(PlayerInitialMiddlehand, 23))
+	})
+
+	t.Run("revolution", func(t *testing.T) {
+		rules := StandardRuleSet()
+		assert.False(t, rules.IsValidBid(92))
+		rules.Revolution = true
+		assert.True(t, rules.IsValidBid(92))
+		assert.Equal(t, 92, rules.NextBid(90))
+	})
+
+	t.Run("no ouvert values with a sealed deal", func(t *testing.T) {
+		rules := testSealedRuleSet()
+		assert.True(t, rules.IsValidBid(35))
+		assert.False(t, rules.IsValidBid(46))
+		assert.False(t, rules.IsValidBid(59))
+		assert.True(t, rules.IsValidBid(204))
+		assert.False(t, rules.IsValidBid(198))
+		assert.False(t, rules.IsValidBid(264))
+		assert.Equal(t, BidNone, rules.NextBid(240))
+	})
+}
+
+func TestGameStateRules(t *testing.T) {
+	t.Run("kontra can be disabled", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.KontraRe = false
+		g := testGetRulesPlayingPhaseGame(t, rules, GameTypeGrand, NoGameModifiers)
+		assert.Equal(t, ErrRuleDisabled, g.Kontra(PlayerInitialForehand))
+		assert.False(t, g.BlindedForPlayer(PlayerInitialForehand).Rules.KontraRe)
+	})
+
+	t.Run("null value follows the rules", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.NullValues.Hand = 30
+		g := testGetRulesPlayingPhaseGame(t, rules, GameTypeNull, NoGameModifiers)
+		testPlayOut(t, g.Playing(), g.PlayCard, -1)
+		assert.Equal(t, 30, g.GetGameValue())
+	})
+
+	t.Run("schneider announcement without hand", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.AnnouncementsRequireHand = false
+		g := testNewDealtGame(t, rules)
+		testWinBidding(t, g)
+		skat := g.GetSkat()
+		assert.Nil(t, g.TakeSkat(PlayerInitialMiddlehand))
+		assert.Nil(t, g.Declare(PlayerInitialMiddlehand, GameTypeGrand, GameModifierSchneiderAnnounced, skat))
+		assert.True(t, g.Modifiers().Test(GameModifierSchneiderAnnounced))
+	})
+}
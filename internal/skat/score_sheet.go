@@ -0,0 +1,85 @@
+package skat
+
+import (
+	"errors"
+)
+
+var (
+	ErrInvalidSeat = errors.New("invalid seat")
+)
+
+// A single line in the score sheet
+//
+// Seats are numbered for the whole session, independent of who was forehand
+// in the game.
+type ScoreSheetEntry struct {
+	// Seat of the declarer, PlayerNone for Junk games
+	Declarer  int      `json:"declarer"`
+	GameType  GameType `json:"gameType"`
+	GameValue int      `json:"gameValue"`
+	Won       bool     `json:"won"`
+	// All players passed; the game has no value
+	PassedIn bool `json:"passedIn,omitempty"`
+	// Score change per seat; a dealer who sat out the game gets zero
+	Scores []int `json:"scores"`
+}
+
+// The running score list (Skatliste) of a session
+type ScoreSheet struct {
+	Entries []ScoreSheetEntry `json:"entries"`
+	Totals  []int             `json:"totals"`
+}
+
+func NewScoreSheet(seats int) *ScoreSheet {
+	return &ScoreSheet{
+		Entries: make([]ScoreSheetEntry, 0),
+		Totals:  make([]int, seats),
+	}
+}
+
+func (s *ScoreSheet) Seats() int {
+	return len(s.Totals)
+}
+
+// Add a scored or passed-in game to the sheet
+//
+// seats maps the player indices of the game to the seats of the session.
+func (s *ScoreSheet) Record(g *GameState, seats [3]int) error {
+	if g.Phase() != PhaseScored && g.Phase() != PhasePassedIn {
+		return ErrGameNotScored
+	}
+	for _, seat := range seats {
+		if seat < 0 || seat >= s.Seats() {
+			return ErrInvalidSeat
+		}
+	}
+
+	entry := ScoreSheetEntry{
+		Declarer:  PlayerNone,
+		GameType:  g.GameType(),
+		GameValue: g.GetGameValue(),
+		Scores:    make([]int, s.Seats()),
+		PassedIn:  g.Phase() == PhasePassedIn,
+	}
+	declarer := g.Declarer()
+	if declarer != PlayerNone && entry.GameType != GameTypeJunk {
+		entry.Declarer = seats[declarer]
+		entry.Won = g.GetLossReason() == ""
+	}
+	for player, seat := range seats {
+		entry.Scores[seat] = g.GetScore(player)
+		s.Totals[seat] = s.Totals[seat] + entry.Scores[seat]
+	}
+	s.Entries = append(s.Entries, entry)
+	return nil
+}
+
+func (s *ScoreSheet) Copy() *ScoreSheet {
+	result := &ScoreSheet{
+		Entries: make([]ScoreSheetEntry, len(s.Entries)),
+		Totals:  make([]int, len(s.Totals)),
+	}
+	copy(result.Entries, s.Entries)
+	copy(result.Totals, s.Totals)
+	return result
+}
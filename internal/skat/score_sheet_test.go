@@ -0,0 +1,71 @@
+package skat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreSheet(t *testing.T) {
+	t.Run("rejects games which have not been scored", func(t *testing.T) {
+		s := NewScoreSheet(3)
+		g := testGetPlayingPhaseGame(t, GameTypeSpades)
+		assert.Equal(t, ErrGameNotScored, s.Record(g, [3]int{0, 1, 2}))
+		assert.Equal(t, 0, len(s.Entries))
+	})
+
+	t.Run("rejects invalid seats", func(t *testing.T) {
+		s := NewScoreSheet(3)
+		g := testGetScoredGame(t, GameTypeSpades, false)
+		assert.Equal(t, ErrInvalidSeat, s.Record(g, [3]int{1, 2, 3}))
+		assert.Equal(t, 0, len(s.Entries))
+	})
+
+	t.Run("maps players to seats", func(t *testing.T) {
+		s := NewScoreSheet(4)
+		g := testGetScoredGame(t, GameTypeSpades, false)
+		seats := [3]int{2, 3, 0}
+		assert.Nil(t, s.Record(g, seats))
+
+		assert.Equal(t, 1, len(s.Entries))
+		entry := s.Entries[0]
+		assert.Equal(t, 3, entry.Declarer)
+		assert.Equal(t, GameTypeSpades, entry.GameType)
+		assert.Equal(t, g.GetGameValue(), entry.GameValue)
+		assert.Equal(t, g.GetLossReason() == "", entry.Won)
+		assert.Equal(t, 0, entry.Scores[1])
+		for player, seat := range seats {
+			assert.Equal(t, g.GetScore(player), entry.Scores[seat])
+		}
+	})
+
+	t.Run("sums up the scores", func(t *testing.T) {
+		s := NewScoreSheet(3)
+		g1 := testGetScoredGame(t, GameTypeSpades, false)
+		g2 := testGetScoredGame(t, GameTypeGrand, false)
+		assert.Nil(t, s.Record(g1, [3]int{0, 1, 2}))
+		assert.Nil(t, s.Record(g2, [3]int{1, 2, 0}))
+		assert.Equal(t, 2, len(s.Entries))
+		assert.Equal(t, g1.GetScore(0)+g2.GetScore(2), s.Totals[0])
+		assert.Equal(t, g1.GetScore(1)+g2.GetScore(0), s.Totals[1])
+		assert.Equal(t, g1.GetScore(2)+g2.GetScore(1), s.Totals[2])
+	})
+
+	t.Run("junk games have no declarer", func(t *testing.T) {
+		s := NewScoreSheet(3)
+		g := testGetJunkPhaseGame(t)
+		testPlayOut(t, g.Playing(), g.PlayCard, -1)
+		assert.Equal(t, PhaseScored, g.Phase())
+		assert.Nil(t, s.Record(g, [3]int{0, 1, 2}))
+		assert.Equal(t, PlayerNone, s.Entries[0].Declarer)
+		assert.False(t, s.Entries[0].Won)
+	})
+
+	t.Run("copy is independent", func(t *testing.T) {
+		s := NewScoreSheet(3)
+		c := s.Copy()
+		assert.Nil(t, s.Record(testGetScoredGame(t, GameTypeSpades, false), [3]int{0, 1, 2}))
+		assert.Equal(t, 0, len(c.Entries))
+		assert.Equal(t, []int{0, 0, 0}, c.Totals)
+	})
+}
@@ -10,6 +10,8 @@ const (
 	LossReasonNoSchwarz       = "no_schwarz"
 	LossReasonNotNull         = "not_null"
 	LossReasonOverbid         = "overbid"
+	LossReasonResigned        = "resigned"
+	LossReasonCheated         = "cheated"
 )
 
 type ScoreFormula struct {
@@ -118,7 +120,7 @@ const (
 )
 
 func (cs CardSet) GetMatadorsJackStrength(gameType GameType) int {
-	if gameType == GameTypeNull {
+	if gameType.IsNull() {
 		return 0
 	}
 
@@ -168,60 +170,16 @@ func (cs CardSet) GetMatadorsJackStrength(gameType GameType) int {
 	return max
 }
 
+// Return the base value of a suit game or grand under the standard rules
+//
+// Returns 0 for all other game types.
+func GetBaseValue(gameType GameType) int {
+	return standardRules.BaseValue(gameType)
+}
+
+// Return the value of a game under the standard rules
 func CalculateGameValue(initialDeclarerHand CardSet, gameType GameType, modifiers GameModifier) (base int, factor int) {
-	factor = 1
-	switch gameType {
-	case GameTypeNull:
-		isHand := modifiers.Test(GameModifierHand)
-		isOuvert := modifiers.Test(GameModifierOuvert)
-		if isHand {
-			if isOuvert {
-				return 59, factor
-			} else {
-				return 35, factor
-			}
-		} else {
-			if isOuvert {
-				return 46, factor
-			} else {
-				return 23, factor
-			}
-		}
-	case GameTypeDiamonds, GameTypeHearts, GameTypeSpades, GameTypeClubs, GameTypeGrand:
-		switch gameType {
-		case GameTypeDiamonds:
-			base = 9
-		case GameTypeHearts:
-			base = 10
-		case GameTypeSpades:
-			base = 11
-		case GameTypeClubs:
-			base = 12
-		case GameTypeGrand:
-			base = 24
-		}
-		factor = 1 + initialDeclarerHand.GetMatadorsJackStrength(gameType)
-		if modifiers.Test(GameModifierHand) {
-			factor = factor + 1
-			if modifiers.Test(GameModifierSchneiderAnnounced) {
-				factor = factor + 1
-			}
-			if modifiers.Test(GameModifierSchwarzAnnounced) {
-				factor = factor + 1
-			}
-		}
-		if modifiers.Test(GameModifierSchneider) {
-			factor = factor + 1
-		}
-		if modifiers.Test(GameModifierSchwarz) {
-			factor = factor + 1
-		}
-		if modifiers.Test(GameModifierOuvert) {
-			factor = factor + 1
-		}
-		return base, factor
-	}
-	return base, factor
+	return standardRules.GameValue(initialDeclarerHand, gameType, modifiers)
 }
 
 func EvaluateWonCards(wonCards [3]CardSet, declarer int) (modifiers GameModifier, declarerScore int, defenderScore int) {
@@ -241,15 +199,20 @@ func EvaluateWonCards(wonCards [3]CardSet, declarer int) (modifiers GameModifier
 	return modifiers, declarerScore, defenderScore
 }
 
+// Evaluate the outcome of a game
+//
+// Kontra and Re in the modifiers double the resulting game value; they do not
+// count towards reaching the bid.
 func EvaluateGame(gameBaseValue int, gameValueFactor int, declarerScore int, declarerBid int, gameType GameType, modifiers GameModifier) (declarerWon bool, gameValue int, lossReason string) {
 	gameValue = gameBaseValue * gameValueFactor
 	if gameValue < declarerBid {
 		// overbid!
 		gameValue = gameBaseValue * ((declarerBid + gameBaseValue - 1) / gameBaseValue)
-		return false, gameValue, LossReasonOverbid
+		return false, gameValue * modifiers.DoublingFactor(), LossReasonOverbid
 	}
+	gameValue = gameValue * modifiers.DoublingFactor()
 
-	if gameType == GameTypeNull {
+	if gameType.IsNull() {
 		if !modifiers.Test(GameModifierSchwarz) || declarerScore > 0 {
 			return false, gameValue, LossReasonNotNull
 		} else {
@@ -270,3 +233,51 @@ func EvaluateGame(gameBaseValue int, gameValueFactor int, declarerScore int, dec
 	}
 	return true, gameValue, ""
 }
+
+// Evaluate a finished Junk game
+//
+// The player with the most card points loses the game and the card points
+// are deducted from their score; if several players share the highest
+// amount, all of them lose. For each player who did not take a single trick
+// (Jungfrau), the value is doubled. If a single player took all tricks
+// (Durchmarsch), that player is awarded 120 points instead and nobody loses.
+func EvaluateJunkGame(wonCards [3]CardSet, tricks [3]int) (scores [3]int, gameValue int, modifiers GameModifier) {
+	points := [3]int{
+		wonCards[0].Value(),
+		wonCards[1].Value(),
+		wonCards[2].Value(),
+	}
+
+	totalTricks := tricks[0] + tricks[1] + tricks[2]
+	for i := range tricks {
+		if tricks[i] == totalTricks {
+			modifiers = modifiers.With(GameModifierDurchmarsch)
+			scores[i] = points[i]
+			return scores, points[i], modifiers
+		}
+	}
+
+	factor := 1
+	for i := range tricks {
+		if tricks[i] == 0 {
+			modifiers = modifiers.With(GameModifierJungfrau)
+			factor = factor * 2
+		}
+	}
+
+	highest := -1
+	for _, p := range points {
+		if p > highest {
+			highest = p
+		}
+	}
+
+	gameValue = highest * factor
+	for i, p := range points {
+		if p == highest {
+			scores[i] = -gameValue
+		}
+	}
+
+	return scores, gameValue, modifiers
+}
@@ -617,6 +617,44 @@ func TestEvaluateGame(t *testing.T) {
 		assert.Equal(t, LossReasonNotNull, reason)
 	})
 
+	t.Run("kontra and re double the game value", func(t *testing.T) {
+		won, gameValue, _ := EvaluateGame(
+			9,
+			2,
+			120,
+			18,
+			0,
+			GameModifierKontra,
+		)
+		assert.True(t, won)
+		assert.Equal(t, 36, gameValue)
+
+		won, gameValue, _ = EvaluateGame(
+			9,
+			2,
+			120,
+			18,
+			0,
+			GameModifierKontra|GameModifierRe,
+		)
+		assert.True(t, won)
+		assert.Equal(t, 72, gameValue)
+	})
+
+	t.Run("kontra does not count towards the bid", func(t *testing.T) {
+		won, gameValue, reason := EvaluateGame(
+			9,
+			2,
+			120,
+			20,
+			0,
+			GameModifierKontra,
+		)
+		assert.False(t, won)
+		assert.Equal(t, 54, gameValue)
+		assert.Equal(t, LossReasonOverbid, reason)
+	})
+
 	t.Run("won null game", func(t *testing.T) {
 		won, gameValue, reason := EvaluateGame(
 			35,
@@ -631,3 +669,40 @@ func TestEvaluateGame(t *testing.T) {
 		assert.Equal(t, "", reason)
 	})
 }
+
+func TestEvaluateJunkGame(t *testing.T) {
+	t.Run("player with most points loses", func(t *testing.T) {
+		cards := testGetOnTheEdgeGame(t)
+		scores, value, modifiers := EvaluateJunkGame(cards, [3]int{3, 4, 3})
+		assert.Equal(t, [3]int{0, -60, 0}, scores)
+		assert.Equal(t, 60, value)
+		assert.Equal(t, NoGameModifiers, modifiers)
+	})
+
+	t.Run("all players sharing the highest points lose", func(t *testing.T) {
+		cards := testGetOnTheEdgeGame(t)
+		cards = [3]CardSet{cards[1], cards[0], cards[2]}
+		cards[1] = append(cards[1], cards[2]...)
+		cards[2] = CardSet{}
+		scores, value, modifiers := EvaluateJunkGame(cards, [3]int{5, 5, 0})
+		assert.Equal(t, 120, value)
+		assert.Equal(t, [3]int{-120, -120, 0}, scores)
+		assert.Equal(t, GameModifierJungfrau, modifiers)
+	})
+
+	t.Run("jungfrau doubles the value", func(t *testing.T) {
+		cards := testPseudoSchwarzGame(t)
+		scores, value, modifiers := EvaluateJunkGame(cards, [3]int{1, 9, 0})
+		assert.Equal(t, 240, value)
+		assert.Equal(t, [3]int{0, -240, 0}, scores)
+		assert.Equal(t, GameModifierJungfrau, modifiers)
+	})
+
+	t.Run("durchmarsch awards all points to the taker", func(t *testing.T) {
+		cards := testSchwarzGame(t)
+		scores, value, modifiers := EvaluateJunkGame(cards, [3]int{0, 10, 0})
+		assert.Equal(t, 120, value)
+		assert.Equal(t, [3]int{0, 120, 0}, scores)
+		assert.Equal(t, GameModifierDurchmarsch, modifiers)
+	})
+}
@@ -0,0 +1,822 @@
+package skat
+
+import (
+	"errors"
+)
+
+var (
+	ErrSealedDeal     = errors.New("not possible while the hands are sealed")
+	ErrDealIncomplete = errors.New("the cards have not been dealt among the players yet")
+	ErrMissingKey     = errors.New("not all players have published a key")
+	ErrKeyPublished   = errors.New("key has already been published")
+	ErrKeyRevealed    = errors.New("key has already been revealed")
+	ErrInvalidShuffle = errors.New("invalid shuffle")
+	ErrInvalidStrip   = errors.New("invalid strip")
+	ErrInvalidSlot    = errors.New("no such card in the hand")
+)
+
+// A position in the shuffled deck
+type SealedSlot struct {
+	// The card, encrypted with the keys of all players who have not stripped
+	// their encryption yet
+	Value    []byte  `json:"value"`
+	Stripped [3]bool `json:"stripped"`
+}
+
+// An encryption removed from a slot, see MentalPokerKey.Strip
+type CardStrip struct {
+	Slot  int            `json:"slot"`
+	Value []byte         `json:"value"`
+	Proof *EqualityProof `json:"proof"`
+}
+
+// The proof which goes with a card played from a sealed hand, see
+// MentalPokerKey.ProveCard
+type CardReveal struct {
+	Slot  int            `json:"slot"`
+	Proof *EqualityProof `json:"proof"`
+}
+
+// A card played from a sealed hand
+type SealedPlay struct {
+	Player int  `json:"player"`
+	Slot   int  `json:"slot"`
+	Card   Card `json:"card"`
+}
+
+// The public state of a mental poker deal
+//
+// The players publish their keys and then shuffle the deck in turn, each
+// encrypting every card with their key. The slots of the shuffled deck are
+// handed out like the cards in DealDeck. The other two players strip their
+// encryption from each slot of a hand, so that only the owner can decrypt it.
+// The keys are revealed after play, so that the game can be audited.
+type SealedDeal struct {
+	PublicKeys [3][]byte `json:"publicKeys"`
+	// Number of players who have shuffled the deck, in turn from forehand
+	Shuffled int `json:"shuffled"`
+	// Output of each shuffle; only kept for the audit
+	Shuffles [][][]byte `json:"shuffles,omitempty"`
+	// The current deck
+	Slots []SealedSlot `json:"slots"`
+	// Slots held by each player which have not been played yet
+	Hands [3][]int `json:"hands"`
+	// Slots of the skat, or of the pushed cards once the declarer took the
+	// skat
+	Skat  []int        `json:"skat"`
+	Plays []SealedPlay `json:"plays,omitempty"`
+	// Only revealed after play
+	SecretKeys [3][]byte `json:"secretKeys"`
+	// Player who takes the cards which were never played after a resignation
+	// or an early decision of the game
+	AwardedTo int `json:"awardedTo"`
+	// Player who was caught cheating in the audit
+	Cheater int `json:"cheater"`
+}
+
+// Return the slots of the hands and the skat, handed out like DealDeck
+func sealedLayout() (hands [3][]int, skat []int) {
+	dealt, dealtSkat, _ := DealDeck(NewCardDeck())
+	for i := range dealt {
+		for _, card := range dealt[i] {
+			hands[i] = append(hands[i], cardIndex(card))
+		}
+	}
+	for _, card := range dealtSkat {
+		skat = append(skat, cardIndex(card))
+	}
+	return hands, skat
+}
+
+func copySlots(slots []int) []int {
+	if slots == nil {
+		return nil
+	}
+	result := make([]int, len(slots))
+	copy(result, slots)
+	return result
+}
+
+// Remove a slot from a list of slots
+func popSlot(slots []int, slot int) ([]int, error) {
+	for i, s := range slots {
+		if s == slot {
+			result := make([]int, 0, len(slots)-1)
+			result = append(result, slots[:i]...)
+			return append(result, slots[i+1:]...), nil
+		}
+	}
+	return slots, ErrInvalidSlot
+}
+
+// Return a deep copy, with or without the shuffles
+func (d *SealedDeal) copy(withShuffles bool) *SealedDeal {
+	result := *d
+	result.Shuffles = nil
+	if withShuffles && d.Shuffles != nil {
+		result.Shuffles = make([][][]byte, len(d.Shuffles))
+		copy(result.Shuffles, d.Shuffles)
+	}
+	if d.Slots != nil {
+		result.Slots = make([]SealedSlot, len(d.Slots))
+		copy(result.Slots, d.Slots)
+	}
+	for i := range d.Hands {
+		result.Hands[i] = copySlots(d.Hands[i])
+	}
+	result.Skat = copySlots(d.Skat)
+	if d.Plays != nil {
+		result.Plays = make([]SealedPlay, len(d.Plays))
+		copy(result.Plays, d.Plays)
+	}
+	return &result
+}
+
+func isSlot(slot int) bool {
+	return slot >= 0 && slot < len(NewCardDeck())
+}
+
+// Check a deal restored from a snapshot
+func (d *SealedDeal) check() error {
+	if d.Shuffled < 0 || d.Shuffled > len(d.PublicKeys) || len(d.Shuffles) > d.Shuffled {
+		return ErrInconsistentSnapshot
+	}
+	if d.Shuffled > 0 && len(d.Slots) != len(NewCardDeck()) {
+		return ErrInconsistentSnapshot
+	}
+	if !isPlayerOrNone(d.AwardedTo) || !isPlayerOrNone(d.Cheater) {
+		return ErrInconsistentSnapshot
+	}
+	seen := make(map[int]bool)
+	slots := append([]int{}, d.Skat...)
+	for _, hand := range d.Hands {
+		slots = append(slots, hand...)
+	}
+	for _, play := range d.Plays {
+		if !isPlayerOrNone(play.Player) || play.Player == PlayerNone {
+			return ErrInconsistentSnapshot
+		}
+		slots = append(slots, play.Slot)
+	}
+	for _, slot := range slots {
+		if !isSlot(slot) || slot >= len(d.Slots) || seen[slot] {
+			return ErrInconsistentSnapshot
+		}
+		seen[slot] = true
+	}
+	return nil
+}
+
+func (d *SealedDeal) keysPublished() bool {
+	for _, key := range d.PublicKeys {
+		if key == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Return true once all players have revealed their secret keys
+func (d *SealedDeal) Audited() bool {
+	for _, key := range d.SecretKeys {
+		if key == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Return the player who has to shuffle next
+//
+// Returns PlayerNone while keys are missing and after the last shuffle.
+func (d *SealedDeal) NextShuffler() int {
+	if !d.keysPublished() || d.Shuffled >= len(d.PublicKeys) {
+		return PlayerNone
+	}
+	return d.Shuffled
+}
+
+// Return the slots from which the player has to strip their encryption
+//
+// These are all slots in the hands of the other players which the player has
+// not stripped yet.
+func (d *SealedDeal) PendingStrips(player int) []int {
+	var result []int
+	for owner, hand := range d.Hands {
+		if owner == player {
+			continue
+		}
+		for _, slot := range hand {
+			if !d.Slots[slot].Stripped[player] {
+				result = append(result, slot)
+			}
+		}
+	}
+	return result
+}
+
+// Return the player who has to strip their encryption next
+//
+// The players strip in turn from forehand. Returns PlayerNone if no
+// encryption needs to be stripped.
+func (d *SealedDeal) NextStripper() int {
+	for player := range d.Hands {
+		if len(d.PendingStrips(player)) > 0 {
+			return player
+		}
+	}
+	return PlayerNone
+}
+
+// Decrypt the slots of the hand of the player
+//
+// Slots from which the other players have not stripped their encryption yet
+// are skipped. The slots are returned in the order of the cards.
+func (d *SealedDeal) OpenHand(player int, key *MentalPokerKey) (CardSet, []int, error) {
+	cards := make(CardSet, 0, len(d.Hands[player]))
+	slots := make([]int, 0, len(d.Hands[player]))
+	for _, slot := range d.Hands[player] {
+		if len(d.PendingStripsOf(slot, player)) > 0 {
+			continue
+		}
+		card, err := key.Decrypt(d.Slots[slot].Value)
+		if err != nil {
+			return nil, nil, err
+		}
+		cards = append(cards, card)
+		slots = append(slots, slot)
+	}
+	return cards, slots, nil
+}
+
+// Return the players other than the owner who have not stripped their
+// encryption from the slot
+func (d *SealedDeal) PendingStripsOf(slot int, owner int) []int {
+	var result []int
+	for player, stripped := range d.Slots[slot].Stripped {
+		if player != owner && !stripped {
+			result = append(result, player)
+		}
+	}
+	return result
+}
+
+// Strip the encryption of the player from all slots pending for them
+func (d *SealedDeal) Strip(player int, key *MentalPokerKey) ([]CardStrip, error) {
+	pending := d.PendingStrips(player)
+	result := make([]CardStrip, len(pending))
+	for i, slot := range pending {
+		value, proof, err := key.Strip(d.Slots[slot].Value)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = CardStrip{
+			Slot:  slot,
+			Value: value,
+			Proof: proof,
+		}
+	}
+	return result, nil
+}
+
+// Find the slots of the given cards in the hand of the player
+func (d *SealedDeal) FindSlots(player int, key *MentalPokerKey, cards CardSet) ([]int, error) {
+	hand, slots, err := d.OpenHand(player, key)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]int, len(cards))
+	for i, card := range cards {
+		found := false
+		for j, c := range hand {
+			if c == card {
+				result[i] = slots[j]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, ErrInvalidSlot
+		}
+	}
+	return result, nil
+}
+
+// Prove that the card is in the hand of the player, so that it can be played
+func (d *SealedDeal) RevealCard(player int, key *MentalPokerKey, card Card) (*CardReveal, error) {
+	slots, err := d.FindSlots(player, key, CardSet{card})
+	if err != nil {
+		return nil, err
+	}
+	proof, err := key.ProveCard(d.Slots[slots[0]].Value, card)
+	if err != nil {
+		return nil, err
+	}
+	return &CardReveal{
+		Slot:  slots[0],
+		Proof: proof,
+	}, nil
+}
+
+// Decrypt every slot with the revealed keys
+//
+// Returns ErrDealMismatch unless the slots hold each card of the deck once.
+func (d *SealedDeal) openSlots() ([]Card, error) {
+	var keys [3]*MentalPokerKey
+	for i, data := range d.SecretKeys {
+		key, err := ParseMentalPokerKey(data)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+	}
+	if len(d.Slots) != len(NewCardDeck()) {
+		return nil, ErrDealMismatch
+	}
+	result := make([]Card, len(d.Slots))
+	seen := make(map[Card]bool)
+	for i, slot := range d.Slots {
+		x, err := decodeElement(slot.Value)
+		if err != nil {
+			return nil, ErrDealMismatch
+		}
+		for player, stripped := range slot.Stripped {
+			if !stripped {
+				x = expElement(x, keys[player].d)
+			}
+		}
+		card, err := elementCard(x)
+		if err != nil || seen[card] {
+			return nil, ErrDealMismatch
+		}
+		seen[card] = true
+		result[i] = card
+	}
+	return result, nil
+}
+
+func slotCards(cards []Card, slots []int) CardSet {
+	result := make(CardSet, len(slots))
+	for i, slot := range slots {
+		result[i] = cards[slot]
+	}
+	return result
+}
+
+// Return the deal of a mental poker game
+//
+// Returns nil in all other games.
+func (g *GameState) SealedDeal() *SealedDeal {
+	d, ok := g.dealing.(*MentalPokerDealing)
+	if !ok {
+		return nil
+	}
+	return &d.deal
+}
+
+// Return the number of cards the player holds
+func (g *GameState) handSize(player int) int {
+	result := len(g.GetHand(player))
+	if d := g.SealedDeal(); d != nil && !d.Audited() {
+		result = result + len(d.Hands[player])
+	}
+	return result
+}
+
+func checkPlayer(player int) error {
+	if player < 0 || player >= 3 {
+		return ErrInvalidPlayer
+	}
+	return nil
+}
+
+// Publish the key the player encrypts the cards with
+func (g *GameState) PublishKey(player int, publicKey []byte) error {
+	d := g.SealedDeal()
+	if d == nil {
+		return ErrRuleDisabled
+	}
+	if g.phase != PhaseInit {
+		return ErrWrongPhase
+	}
+	if err := checkPlayer(player); err != nil {
+		return err
+	}
+	if d.PublicKeys[player] != nil {
+		return ErrKeyPublished
+	}
+	if _, err := decodeElement(publicKey); err != nil {
+		return ErrInvalidKey
+	}
+	d.PublicKeys[player] = publicKey
+	return nil
+}
+
+// Hand in the deck as encrypted and shuffled by the player
+//
+// The players shuffle in turn once all keys have been published. The first
+// shuffle starts from MentalPokerDeck, every further one from the current
+// slots. Whether the player actually encrypted the deck with their key is
+// only checked in the audit.
+func (g *GameState) ShuffleDeck(player int, deck [][]byte) error {
+	d := g.SealedDeal()
+	if d == nil {
+		return ErrRuleDisabled
+	}
+	if g.phase != PhaseInit {
+		return ErrWrongPhase
+	}
+	if !d.keysPublished() {
+		return ErrMissingKey
+	}
+	if player != d.NextShuffler() {
+		return ErrNotYourTurn
+	}
+	if len(deck) != len(NewCardDeck()) {
+		return ErrInvalidShuffle
+	}
+	seen := make(map[string]bool)
+	slots := make([]SealedSlot, len(deck))
+	for i, value := range deck {
+		if _, err := decodeElement(value); err != nil || seen[string(value)] {
+			return ErrInvalidShuffle
+		}
+		seen[string(value)] = true
+		slots[i].Value = value
+	}
+	d.Shuffles = append(d.Shuffles, deck)
+	d.Shuffled = d.Shuffled + 1
+	d.Slots = slots
+	if d.NextShuffler() == PlayerNone {
+		d.Hands, d.Skat = sealedLayout()
+	}
+	return nil
+}
+
+// Strip the encryption of the player from the slots of the other players
+//
+// The strips must cover exactly the pending strips of the player, see
+// SealedDeal.PendingStrips. The cards are dealt once nothing is left to
+// strip. After the declarer took the skat, the defenders strip the skat the
+// same way.
+func (g *GameState) StripCards(player int, strips []CardStrip) error {
+	d := g.SealedDeal()
+	if d == nil {
+		return ErrRuleDisabled
+	}
+	if g.phase != PhaseInit && g.phase != PhaseDeclaration {
+		return ErrWrongPhase
+	}
+	if player != d.NextStripper() {
+		return ErrNotYourTurn
+	}
+	pending := make(map[int]bool)
+	for _, slot := range d.PendingStrips(player) {
+		pending[slot] = true
+	}
+	if len(strips) != len(pending) {
+		return ErrInvalidStrip
+	}
+	for _, strip := range strips {
+		if !pending[strip.Slot] {
+			return ErrInvalidStrip
+		}
+		delete(pending, strip.Slot)
+		if err := VerifyStrip(d.PublicKeys[player], d.Slots[strip.Slot].Value, strip.Value, strip.Proof); err != nil {
+			return err
+		}
+	}
+	for _, strip := range strips {
+		d.Slots[strip.Slot].Value = strip.Value
+		d.Slots[strip.Slot].Stripped[player] = true
+	}
+	if g.phase == PhaseInit {
+		return g.dealIfReady()
+	}
+	return nil
+}
+
+// Declare a game from a sealed hand
+//
+// Instead of cards, the declarer pushes two slots of their hand. After
+// taking the skat, the declarer can only push once the defenders have
+// stripped it.
+func (g *GameState) DeclareSealed(player int, gameType GameType, announcedModifiers GameModifier, slotsToPush []int) error {
+	d := g.SealedDeal()
+	if d == nil {
+		return ErrRuleDisabled
+	}
+	newModifiers, err := g.checkDeclaration(player, gameType, announcedModifiers, len(slotsToPush))
+	if err != nil {
+		return err
+	}
+	if newModifiers.Test(GameModifierOuvert) {
+		return ErrSealedDeal
+	}
+	if d.NextStripper() != PlayerNone {
+		return ErrDealIncomplete
+	}
+	hand := d.Hands[player]
+	for _, slot := range slotsToPush {
+		if hand, err = popSlot(hand, slot); err != nil {
+			return ErrInvalidPush
+		}
+	}
+	d.Hands[player] = hand
+	if len(slotsToPush) > 0 {
+		d.Skat = copySlots(slotsToPush)
+	}
+	g.modifiers = newModifiers
+	g.startPlaying(gameType, nil)
+	return nil
+}
+
+// Play a card from a sealed hand
+//
+// The reveal proves that the slot holds the card. Whether the player had to
+// follow suit with another card can only be checked in the audit.
+func (g *GameState) PlaySealedCard(player int, card Card, reveal *CardReveal) error {
+	d := g.SealedDeal()
+	if d == nil {
+		return ErrRuleDisabled
+	}
+	if g.phase != PhasePlaying {
+		return ErrWrongPhase
+	}
+	if g.claim != nil {
+		return ErrClaimPending
+	}
+	if player != g.playingState.GetCurrentPlayer() {
+		return ErrNotYourTurn
+	}
+	if reveal == nil {
+		return ErrInvalidProof
+	}
+	hand, err := popSlot(d.Hands[player], reveal.Slot)
+	if err != nil {
+		return err
+	}
+	if len(d.PendingStripsOf(reveal.Slot, player)) > 0 {
+		return ErrDealIncomplete
+	}
+	if err := VerifyCard(d.PublicKeys[player], d.Slots[reveal.Slot].Value, card, reveal.Proof); err != nil {
+		return err
+	}
+
+	// the card only enters the hand to be played right away
+	playing := &g.playingState.players[player]
+	playing.Hand = append(playing.Hand, card)
+	prevHand := d.Hands[player]
+	d.Hands[player] = hand
+	d.Plays = append(d.Plays, SealedPlay{
+		Player: player,
+		Slot:   reveal.Slot,
+		Card:   card,
+	})
+	if err := g.playCard(player, card); err != nil {
+		if g.phase == PhasePlaying {
+			playing.Hand, _ = playing.Hand.Pop(card)
+			d.Hands[player] = prevHand
+			d.Plays = d.Plays[:len(d.Plays)-1]
+		}
+		return err
+	}
+	return nil
+}
+
+// Hand the cards which were not played to a player, see
+// PlayingState.AwardRemainingCards
+//
+// With a sealed deal, this happens in the audit.
+func (g *GameState) awardRemainingCards(player int) {
+	if d := g.SealedDeal(); d != nil {
+		d.AwardedTo = player
+		return
+	}
+	g.playingState.AwardRemainingCards(player)
+}
+
+// Transition PhasePlaying/PhaseDeclaration -> PhaseAudit
+//
+// Returns ErrWrongPhase if sealed cards are left to play.
+func (g *GameState) startAudit(d *SealedDeal) error {
+	if d.AwardedTo == PlayerNone && g.playingState != nil {
+		for _, hand := range d.Hands {
+			if len(hand) > 0 {
+				return ErrWrongPhase
+			}
+		}
+	}
+	g.phase = PhaseAudit
+	return nil
+}
+
+// Reveal the secret key of a player after play
+//
+// Once all keys are known, every card is decrypted and the game is audited:
+// each player must have shuffled with their own key and followed suit. The
+// game is scored right away; if the audit catches a cheater, the side of the
+// cheater loses.
+func (g *GameState) RevealKey(player int, secretKey []byte) error {
+	d := g.SealedDeal()
+	if d == nil {
+		return ErrRuleDisabled
+	}
+	if g.phase != PhaseAudit {
+		return ErrWrongPhase
+	}
+	if err := checkPlayer(player); err != nil {
+		return err
+	}
+	if d.SecretKeys[player] != nil {
+		return ErrKeyRevealed
+	}
+	key, err := ParseMentalPokerKey(secretKey)
+	if err != nil {
+		return err
+	}
+	if !key.Matches(d.PublicKeys[player]) {
+		return ErrInvalidKey
+	}
+	d.SecretKeys[player] = secretKey
+	if !d.Audited() {
+		return nil
+	}
+	return g.audit(d)
+}
+
+// Give up the secret key of a player who did not reveal it in time
+//
+// The game cannot be audited without the key, so the player is treated as a
+// cheater and the game is scored right away. The server applies this once the
+// deadline for revealing the keys has passed.
+func (g *GameState) ForfeitKey(player int) error {
+	d := g.SealedDeal()
+	if d == nil {
+		return ErrRuleDisabled
+	}
+	if g.phase != PhaseAudit {
+		return ErrWrongPhase
+	}
+	if err := checkPlayer(player); err != nil {
+		return err
+	}
+	if d.SecretKeys[player] != nil {
+		return ErrKeyRevealed
+	}
+	g.scoreCheat(d, player)
+	return nil
+}
+
+func (g *GameState) audit(d *SealedDeal) error {
+	input := MentalPokerDeck()
+	for i, output := range d.Shuffles {
+		key, err := ParseMentalPokerKey(d.SecretKeys[i])
+		if err != nil {
+			return err
+		}
+		if !key.VerifyShuffle(input, output) {
+			g.scoreCheat(d, i)
+			return nil
+		}
+		input = output
+	}
+	cards, err := d.openSlots()
+	if err != nil {
+		return err
+	}
+
+	hands, skat := sealedLayout()
+	for i := range g.players {
+		g.players[i].DealtHand = slotCards(cards, hands[i])
+		g.players[i].Hand = g.players[i].DealtHand.Copy()
+	}
+	g.dealtSkat = slotCards(cards, skat)
+	g.skat = g.dealtSkat.Copy()
+
+	if g.playingState == nil {
+		// the declarer resigned before declaring
+		g.evaluateResignation()
+		return nil
+	}
+	if cheater := g.replaySealedPlay(d, cards); cheater != PlayerNone {
+		g.scoreCheat(d, cheater)
+		return nil
+	}
+	g.scoreGame()
+	return nil
+}
+
+// Play the game again with the decrypted hands
+//
+// Returns the player who did not follow suit, if any.
+func (g *GameState) replaySealedPlay(d *SealedDeal, cards []Card) int {
+	hands := [3]*CardSet{
+		&g.players[0].Hand,
+		&g.players[1].Hand,
+		&g.players[2].Hand,
+	}
+	declarer := g.playingState.Declarer()
+	if declarer == PlayerNone {
+		g.playingState = NewJunkPlayingState(hands, g.skat)
+	} else {
+		skatCards := g.skat
+		if !g.modifiers.Test(GameModifierHand) {
+			g.pushed = slotCards(cards, d.Skat)
+			skatCards = g.pushed
+			hand := append(g.players[declarer].Hand, g.skat...)
+			for _, card := range g.pushed {
+				hand, _ = hand.Pop(card)
+			}
+			g.players[declarer].Hand = hand
+		}
+		g.playingState = NewPlayingState(declarer, g.playingState.GameType(), hands, skatCards)
+		// the skat counts towards the hand of the declarer, as in
+		// startPlaying
+		for _, card := range g.skat {
+			g.players[declarer].Hand, _ = g.players[declarer].Hand.Push(card)
+		}
+	}
+
+	for _, play := range d.Plays {
+		if err := g.playingState.Play(play.Player, play.Card); err != nil {
+			return play.Player
+		}
+	}
+	if d.AwardedTo != PlayerNone {
+		g.playingState.AwardRemainingCards(d.AwardedTo)
+	}
+	return PlayerNone
+}
+
+// Score a game in which the audit caught a cheater
+//
+// The side of the cheater loses the declared game, at least at the value of
+// the bid, as in an overbid game; cheating must not cost less than losing. In
+// a Junk game, the cheater alone loses as if they had taken all cards.
+func (g *GameState) scoreCheat(d *SealedDeal, cheater int) {
+	d.Cheater = cheater
+	declarer := g.Declarer()
+	if declarer == PlayerNone {
+		g.finalGameValue = 120 * g.multiplier
+		for i := range g.players {
+			g.players[i].Score = 0
+		}
+		g.players[cheater].Score = -g.finalGameValue
+		g.phase = PhaseScored
+		return
+	}
+
+	declarerWon := cheater != declarer
+	gameValue := g.declaredGameValue() * g.multiplier
+	g.finalGameValue = gameValue
+	playerScores := g.scoring.CalculateScore(gameValue, declarer, declarerWon)
+	for i := range g.players {
+		g.players[i].Score = playerScores[i]
+	}
+	if !declarerWon {
+		g.lossReason = LossReasonCheated
+	}
+	g.phase = PhaseScored
+}
+
+// Return the value of the declared game as the declarer loses it
+//
+// Without the hand of the declarer, e.g. if their key was withheld, the
+// declarer is counted with one matador. Before a game is declared, the bid is
+// lost.
+func (g *GameState) declaredGameValue() int {
+	calledValue := g.biddingState.CalledGameValue()
+	if g.playingState == nil {
+		return calledValue * g.modifiers.DoublingFactor()
+	}
+	gameType := g.playingState.GameType()
+	hand := g.players[g.Declarer()].Hand
+	if len(hand) == 0 {
+		// with the club jack but not the spade jack: "with one"
+		hand = CardSet{SuitClubs.As(CardJack), SuitHearts.As(CardJack)}
+	}
+	base, factor := g.rules.GameValue(hand, gameType, g.modifiers)
+	_, gameValue, _ := EvaluateGame(base, factor, 0, calledValue, gameType, g.modifiers)
+	return gameValue
+}
+
+// Fill in the hand of a player from a sealed deal
+//
+// The hand and the legal cards are decrypted with the key of the player,
+// which the server does not know.
+func (gs *BlindedGameState) Unseal(player int, key *MentalPokerKey) error {
+	d := gs.SealedDeal
+	if d == nil || player == PlayerNone || d.Shuffled < len(d.PublicKeys) {
+		return nil
+	}
+	hand, _, err := d.OpenHand(player, key)
+	if err != nil {
+		return err
+	}
+	gs.Hand = hand
+	if gs.Phase == PhasePlaying && gs.CurrentPlayer == player && gs.Claim == nil {
+		gs.LegalCards = legalCards(hand, gs.Table, gs.GameType)
+	}
+	return nil
+}
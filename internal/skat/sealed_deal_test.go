@@ -0,0 +1,379 @@
+package skat
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testSealedRuleSet() *RuleSet {
+	rules := StandardRuleSet()
+	rules.Dealing = DealingMentalPoker
+	return rules
+}
+
+// Strip the encryption of each player in turn until nothing is pending
+func testStripAll(t *testing.T, g *GameState, keys [3]*MentalPokerKey) {
+	d := g.SealedDeal()
+	for d.NextStripper() != PlayerNone {
+		player := d.NextStripper()
+		strips, err := d.Strip(player, keys[player])
+		assert.Nil(t, err)
+		assert.Nil(t, g.StripCards(player, strips))
+	}
+}
+
+var (
+	testSealedDealOnce sync.Once
+	testSealedSnapshot *GameSnapshot
+	testSealedKeys     [3]*MentalPokerKey
+	testSealedHands    [3]CardSet
+)
+
+// Deal a sealed game and return the keys and the hands as decrypted by the
+// players
+//
+// The deal is expensive, so it is only done once and restored from a
+// snapshot afterwards.
+func testGetSealedBiddingPhaseGame(t *testing.T) (*GameState, [3]*MentalPokerKey, [3]CardSet) {
+	testSealedDealOnce.Do(func() {
+		g, keys, hands := testDealSealedGame(t)
+		testSealedSnapshot = g.Snapshot()
+		testSealedKeys = keys
+		testSealedHands = hands
+	})
+	g, err := RestoreGame(testSealedSnapshot)
+	assert.Nil(t, err)
+	return g, testSealedKeys, testSealedHands
+}
+
+func testDealSealedGame(t *testing.T) (*GameState, [3]*MentalPokerKey, [3]CardSet) {
+	g, err := NewGame(false, LeagueScoreDefinition(), testSealedRuleSet())
+	assert.Nil(t, err)
+	keys := testMentalPokerKeys(t)
+	for i, key := range keys {
+		assert.Nil(t, g.PublishKey(i, key.Public()))
+	}
+	d := g.SealedDeal()
+	deck := MentalPokerDeck()
+	for i, key := range keys {
+		deck, err = key.ShuffleDeck(deck)
+		assert.Nil(t, err)
+		assert.Nil(t, g.ShuffleDeck(i, deck))
+	}
+	assert.Equal(t, PhaseInit, g.Phase())
+	testStripAll(t, g, keys)
+	assert.Equal(t, PhaseBidding, g.Phase())
+
+	var hands [3]CardSet
+	for i, key := range keys {
+		hands[i], _, err = d.OpenHand(i, key)
+		assert.Nil(t, err)
+		assert.Equal(t, 10, len(hands[i]))
+		assert.Equal(t, 0, len(g.GetHand(i)))
+	}
+	return g, keys, hands
+}
+
+func testGetSealedDeclarationPhaseGame(t *testing.T) (*GameState, [3]*MentalPokerKey, [3]CardSet) {
+	g, keys, hands := testGetSealedBiddingPhaseGame(t)
+	testWinBidding(t, g)
+	assert.Equal(t, PhaseDeclaration, g.Phase())
+	return g, keys, hands
+}
+
+// Play a card from the sealed hand of the current player
+//
+// If renege is set, the player does not follow suit if they can. Returns true
+// if the player reneged.
+func testPlaySealedCard(t *testing.T, g *GameState, keys [3]*MentalPokerKey, renege bool) bool {
+	player := g.Playing().GetCurrentPlayer()
+	gs := g.BlindedForPlayer(player)
+	assert.Nil(t, gs.Unseal(player, keys[player]))
+	card := gs.LegalCards[0]
+	reneged := false
+	if renege {
+		for _, c := range gs.Hand {
+			if !gs.LegalCards.Contains(c) {
+				card = c
+				reneged = true
+				break
+			}
+		}
+	}
+	reveal, err := g.SealedDeal().RevealCard(player, keys[player], card)
+	assert.Nil(t, err)
+	assert.Nil(t, g.PlaySealedCard(player, card, reveal))
+	return reneged
+}
+
+func testRevealKeys(t *testing.T, g *GameState, keys [3]*MentalPokerKey) {
+	assert.Equal(t, PhaseAudit, g.Phase())
+	for i, key := range keys {
+		assert.Nil(t, g.RevealKey(i, key.Bytes()))
+	}
+	assert.Equal(t, PhaseScored, g.Phase())
+}
+
+func TestSealedDeal(t *testing.T) {
+	t.Run("plays and audits a game with a pushed skat", func(t *testing.T) {
+		g, keys, hands := testGetSealedDeclarationPhaseGame(t)
+		d := g.SealedDeal()
+		declarer := PlayerInitialMiddlehand
+
+		assert.Nil(t, g.TakeSkat(declarer))
+		assert.Equal(t, PlayerInitialForehand, d.NextStripper())
+		assert.Equal(t, ErrDealIncomplete, g.DeclareSealed(declarer, GameTypeGrand, NoGameModifiers, d.Hands[declarer][:2]))
+		testStripAll(t, g, keys)
+
+		hand, slots, err := d.OpenHand(declarer, keys[declarer])
+		assert.Nil(t, err)
+		assert.Equal(t, 12, len(hand))
+		assert.Equal(t, 12, g.BlindedForPlayer(PlayerInitialForehand).Players[declarer].Ncards)
+		pushed := slots[:2]
+		assert.Nil(t, g.DeclareSealed(declarer, GameTypeGrand, NoGameModifiers, pushed))
+		assert.Equal(t, PhasePlaying, g.Phase())
+
+		for i := 0; i < 30; i = i + 1 {
+			testPlaySealedCard(t, g, keys, false)
+		}
+		assert.Equal(t, PhaseAudit, g.Phase())
+		assert.Nil(t, g.Reveal())
+		testRevealKeys(t, g, keys)
+		assert.Equal(t, PlayerNone, d.Cheater)
+		assert.NotEqual(t, LossReasonCheated, g.GetLossReason())
+
+		reveal := g.Reveal()
+		for i := range hands {
+			assert.True(t, sameCards(hands[i], reveal.Hands[i]))
+		}
+		assert.True(t, sameCards(hand[:2], reveal.Pushed))
+		assert.NotEqual(t, 0, g.GetScore(declarer))
+
+		for i := range hands {
+			assert.Nil(t, VerifySealedDeal(g.BlindedForPlayer(i), i, hands[i]))
+		}
+		assert.Equal(t, ErrDealMismatch, VerifySealedDeal(g.BlindedForPlayer(0), 0, hands[1]))
+	})
+
+	t.Run("stripped slots do not give the cards away", func(t *testing.T) {
+		g, _, _ := testGetSealedBiddingPhaseGame(t)
+		d := g.SealedDeal()
+		for i, hand := range d.Hands {
+			values := make([]*big.Int, 0, len(hand))
+			for _, slot := range hand {
+				value := d.Slots[slot].Value
+				for _, publicKey := range d.PublicKeys {
+					assert.NotEqual(t, publicKey, value)
+				}
+				values = append(values, new(big.Int).SetBytes(value))
+			}
+			// the slots of a hand are only encrypted with the key of player i
+			assert.False(t, testHasProductRelation(values), "hand %d", i)
+		}
+	})
+
+	t.Run("does not reveal the hands before the audit", func(t *testing.T) {
+		g, keys, _ := testGetSealedDeclarationPhaseGame(t)
+		assert.Nil(t, g.Declare(PlayerInitialMiddlehand, GameTypeClubs, NoGameModifiers, nil))
+		for i := range keys {
+			gs := g.BlindedForPlayer(i)
+			assert.Equal(t, 0, len(gs.Hand))
+			assert.Nil(t, gs.SealedDeal.Shuffles)
+			for j := range gs.Players {
+				assert.Equal(t, 10, gs.Players[j].Ncards)
+			}
+		}
+		hands, skat := g.Dealt()
+		assert.Equal(t, 0, len(hands[0]))
+		assert.Equal(t, 0, len(skat))
+	})
+
+	t.Run("rejects features which need the hands", func(t *testing.T) {
+		g, keys, hands := testGetSealedDeclarationPhaseGame(t)
+		player := PlayerInitialMiddlehand
+		assert.Equal(t, ErrSealedDeal, g.Declare(player, GameTypeGrand, GameModifierOuvert, nil))
+		assert.Nil(t, g.TakeSkat(player))
+		assert.Equal(t, ErrSealedDeal, g.Declare(player, GameTypeGrand, NoGameModifiers, hands[player][:2]))
+		testStripAll(t, g, keys)
+		_, slots, err := g.SealedDeal().OpenHand(player, keys[player])
+		assert.Nil(t, err)
+		assert.Nil(t, g.DeclareSealed(player, GameTypeGrand, NoGameModifiers, slots[:2]))
+
+		assert.Equal(t, ErrSealedDeal, g.PlayCard(PlayerInitialForehand, hands[PlayerInitialForehand][0]))
+		assert.Equal(t, ErrSealedDeal, g.Claim(player, 0))
+		assert.Equal(t, ErrSealedDeal, g.Peek(player, PeekSkat))
+	})
+
+	t.Run("rejects proofs for other cards", func(t *testing.T) {
+		g, keys, hands := testGetSealedDeclarationPhaseGame(t)
+		assert.Nil(t, g.Declare(PlayerInitialMiddlehand, GameTypeGrand, NoGameModifiers, nil))
+		player := PlayerInitialForehand
+		reveal, err := g.SealedDeal().RevealCard(player, keys[player], hands[player][0])
+		assert.Nil(t, err)
+		assert.Equal(t, ErrInvalidProof, g.PlaySealedCard(player, hands[player][1], reveal))
+		assert.Equal(t, ErrInvalidSlot, g.PlaySealedCard(player, hands[player][0], &CardReveal{
+			Slot:  g.SealedDeal().Hands[PlayerInitialRearhand][0],
+			Proof: reveal.Proof,
+		}))
+		assert.Nil(t, g.PlaySealedCard(player, hands[player][0], reveal))
+	})
+
+	t.Run("rejects keys which were not published", func(t *testing.T) {
+		g, keys, _ := testGetSealedDeclarationPhaseGame(t)
+		assert.Nil(t, g.Resign(PlayerInitialMiddlehand))
+		assert.Equal(t, PhaseAudit, g.Phase())
+		assert.Equal(t, ErrInvalidKey, g.RevealKey(0, keys[1].Bytes()))
+		testRevealKeys(t, g, keys)
+		assert.Equal(t, LossReasonResigned, g.GetLossReason())
+	})
+
+	t.Run("forfeits a key which is withheld", func(t *testing.T) {
+		g, keys, _ := testGetSealedDeclarationPhaseGame(t)
+		declarer := PlayerInitialMiddlehand
+		assert.Nil(t, g.Declare(declarer, GameTypeGrand, NoGameModifiers, nil))
+		assert.Equal(t, ErrWrongPhase, g.ForfeitKey(declarer))
+		assert.Nil(t, g.Resign(declarer))
+		assert.Equal(t, PhaseAudit, g.Phase())
+		assert.Nil(t, g.RevealKey(PlayerInitialForehand, keys[PlayerInitialForehand].Bytes()))
+		assert.Equal(t, ErrKeyRevealed, g.ForfeitKey(PlayerInitialForehand))
+		assert.Equal(t, ErrInvalidPlayer, g.ForfeitKey(3))
+
+		assert.Nil(t, g.ForfeitKey(declarer))
+		assert.Equal(t, PhaseScored, g.Phase())
+		assert.Equal(t, declarer, g.SealedDeal().Cheater)
+		assert.False(t, g.SealedDeal().Audited())
+		assert.Equal(t, LossReasonCheated, g.GetLossReason())
+		assert.True(t, g.GetScore(declarer) < 0)
+		// Grand Hand with at least one matador
+		assert.Equal(t, 72, g.GetGameValue())
+		assert.Equal(t, ErrWrongPhase, g.RevealKey(declarer, keys[declarer].Bytes()))
+	})
+
+	t.Run("forfeits a withheld key against the defenders", func(t *testing.T) {
+		g, keys, _ := testGetSealedDeclarationPhaseGame(t)
+		declarer := PlayerInitialMiddlehand
+		assert.Nil(t, g.Declare(declarer, GameTypeGrand, NoGameModifiers, nil))
+		assert.Nil(t, g.Resign(declarer))
+		assert.Nil(t, g.RevealKey(declarer, keys[declarer].Bytes()))
+		assert.Nil(t, g.RevealKey(PlayerInitialForehand, keys[PlayerInitialForehand].Bytes()))
+		assert.Nil(t, g.ForfeitKey(PlayerInitialRearhand))
+		assert.Equal(t, PlayerInitialRearhand, g.SealedDeal().Cheater)
+		assert.Equal(t, "", g.GetLossReason())
+		assert.True(t, g.GetScore(declarer) > 0)
+	})
+
+	t.Run("scores a renege against the side of the cheater", func(t *testing.T) {
+		g, keys, _ := testGetSealedDeclarationPhaseGame(t)
+		declarer := PlayerInitialMiddlehand
+		assert.Nil(t, g.Declare(declarer, GameTypeGrand, NoGameModifiers, nil))
+		cheater := PlayerNone
+		for g.Phase() == PhasePlaying {
+			player := g.Playing().GetCurrentPlayer()
+			if testPlaySealedCard(t, g, keys, cheater == PlayerNone && player == declarer) {
+				cheater = player
+			}
+		}
+		if cheater == PlayerNone {
+			t.Skip("the declarer could always follow suit")
+		}
+		testRevealKeys(t, g, keys)
+		assert.Equal(t, cheater, g.SealedDeal().Cheater)
+		assert.Equal(t, LossReasonCheated, g.GetLossReason())
+		assert.True(t, g.GetScore(declarer) < 0)
+		base, factor := g.rules.GameValue(g.players[declarer].Hand, GameTypeGrand, GameModifierHand)
+		assert.Equal(t, base*factor, g.GetGameValue())
+	})
+
+	t.Run("audits a Junk game", func(t *testing.T) {
+		g, keys, hands := testGetSealedBiddingPhaseGame(t)
+		testPassBidding(t, g)
+		assert.Equal(t, GameTypeJunk, g.GameType())
+		for g.Phase() == PhasePlaying {
+			testPlaySealedCard(t, g, keys, false)
+		}
+		testRevealKeys(t, g, keys)
+		assert.Equal(t, PlayerNone, g.SealedDeal().Cheater)
+		total := 0
+		for i := range hands {
+			total = total + g.players[i].WonCards.Value()
+		}
+		assert.Equal(t, 120, total)
+	})
+
+	t.Run("restores from a snapshot", func(t *testing.T) {
+		g, keys, hands := testGetSealedDeclarationPhaseGame(t)
+		assert.Nil(t, g.Declare(PlayerInitialMiddlehand, GameTypeGrand, NoGameModifiers, nil))
+		for i := 0; i < 4; i = i + 1 {
+			testPlaySealedCard(t, g, keys, false)
+		}
+		data, err := json.Marshal(g.Snapshot())
+		assert.Nil(t, err)
+		snap := &GameSnapshot{}
+		assert.Nil(t, json.Unmarshal(data, snap))
+		g, err = RestoreGame(snap)
+		assert.Nil(t, err)
+
+		for g.Phase() == PhasePlaying {
+			testPlaySealedCard(t, g, keys, false)
+		}
+		testRevealKeys(t, g, keys)
+		assert.Equal(t, PlayerNone, g.SealedDeal().Cheater)
+		for i := range hands {
+			assert.Nil(t, VerifySealedDeal(g.BlindedForPlayer(i), i, hands[i]))
+		}
+	})
+}
+
+func TestSealedDealSetup(t *testing.T) {
+	t.Run("is disabled with seeded dealing", func(t *testing.T) {
+		g := testGetInitPhaseGame(t)
+		key, err := GenerateMentalPokerKey()
+		assert.Nil(t, err)
+		assert.Equal(t, ErrRuleDisabled, g.PublishKey(0, key.Public()))
+		assert.Nil(t, g.SealedDeal())
+	})
+
+	t.Run("shuffles in turn once all keys are published", func(t *testing.T) {
+		g, err := NewGame(false, LeagueScoreDefinition(), testSealedRuleSet())
+		assert.Nil(t, err)
+		keys := testMentalPokerKeys(t)
+		deck := MentalPokerDeck()
+		assert.Nil(t, g.PublishKey(0, keys[0].Public()))
+		assert.Equal(t, ErrKeyPublished, g.PublishKey(0, keys[0].Public()))
+		assert.Equal(t, ErrMissingKey, g.ShuffleDeck(0, deck))
+		assert.Nil(t, g.PublishKey(1, keys[1].Public()))
+		assert.Nil(t, g.PublishKey(2, keys[2].Public()))
+		assert.Equal(t, ErrNotYourTurn, g.ShuffleDeck(1, deck))
+		assert.Equal(t, ErrInvalidShuffle, g.ShuffleDeck(0, deck[:31]))
+		duplicate := append([][]byte{deck[1]}, deck[1:]...)
+		assert.Equal(t, ErrInvalidShuffle, g.ShuffleDeck(0, duplicate))
+		assert.Nil(t, g.ShuffleDeck(0, deck))
+		assert.Equal(t, 1, g.SealedDeal().NextShuffler())
+	})
+
+	t.Run("rejects strips out of turn", func(t *testing.T) {
+		g, err := NewGame(false, LeagueScoreDefinition(), testSealedRuleSet())
+		assert.Nil(t, err)
+		keys := testMentalPokerKeys(t)
+		deck := MentalPokerDeck()
+		for i, key := range keys {
+			assert.Nil(t, g.PublishKey(i, key.Public()))
+		}
+		for i := range keys {
+			assert.Nil(t, g.ShuffleDeck(i, deck))
+		}
+		d := g.SealedDeal()
+		strips, err := d.Strip(1, keys[1])
+		assert.Nil(t, err)
+		assert.Equal(t, ErrNotYourTurn, g.StripCards(1, strips))
+		strips, err = d.Strip(0, keys[0])
+		assert.Nil(t, err)
+		assert.Equal(t, ErrInvalidStrip, g.StripCards(0, strips[1:]))
+		strips[0].Value = strips[1].Value
+		assert.Equal(t, ErrInvalidProof, g.StripCards(0, strips))
+	})
+}
@@ -0,0 +1,483 @@
+package skat
+
+import (
+	"errors"
+)
+
+const (
+	// Version of the snapshot format written by Snapshot()
+	SnapshotVersion = 2
+)
+
+var (
+	ErrSnapshotVersion      = errors.New("unsupported snapshot version")
+	ErrInconsistentSnapshot = errors.New("inconsistent snapshot")
+)
+
+type BiddingPlayerSnapshot struct {
+	LastBid      int  `json:"lastBid"`
+	HasPassedBid bool `json:"hasPassedBid"`
+}
+
+type BiddingSnapshot struct {
+	Players          [3]BiddingPlayerSnapshot `json:"players"`
+	Declarer         int                      `json:"declarer"`
+	AwaitingResponse bool                     `json:"awaitingResponse"`
+	LastBid          int                      `json:"lastBid"`
+}
+
+type PlayingPlayerSnapshot struct {
+	Hand     CardSet `json:"hand"`
+	WonCards CardSet `json:"wonCards"`
+	Tricks   int     `json:"tricks"`
+}
+
+type PlayingSnapshot struct {
+	Forehand        int                      `json:"forehand"`
+	Current         int                      `json:"current"`
+	Declarer        int                      `json:"declarer"`
+	GameType        GameType                 `json:"gameType"`
+	LastTrick       Trick                    `json:"lastTrick"`
+	LastTrickWinner int                      `json:"lastTrickWinner"`
+	Table           CardSet                  `json:"table"`
+	History         []TrickRecord            `json:"history"`
+	Players         [3]PlayingPlayerSnapshot `json:"players"`
+	Skat            CardSet                  `json:"skat,omitempty"`
+}
+
+type PlayerSnapshot struct {
+	SeedCommitment   []byte  `json:"seedCommitment"`
+	Seed             []byte  `json:"seed"`
+	Hand             CardSet `json:"hand"`
+	DealtHand        CardSet `json:"dealtHand"`
+	WonCards         CardSet `json:"wonCards"`
+	Score            int     `json:"score"`
+	Resigned         bool    `json:"resigned"`
+	Exchanged        bool    `json:"exchanged"`
+	PeekingLastTrick bool    `json:"peekingLastTrick"`
+	PeekingSkat      bool    `json:"peekingSkat"`
+}
+
+// The complete state of a game, suitable for serialisation
+type GameSnapshot struct {
+	Version             int               `json:"version"`
+	Phase               GamePhase         `json:"phase"`
+	WithDealer          bool              `json:"withDealer"`
+	ServerSeed          []byte            `json:"serverSeed"`
+	ServerCommitment    []byte            `json:"serverCommitment"`
+	DealerSeed          []byte            `json:"dealerSeed"`
+	DealerCommitment    []byte            `json:"dealerCommitment"`
+	DealerLookingAtHand int               `json:"dealerLookingAtHand"`
+	Scoring             ScoreDefinition   `json:"scoring"`
+	Rules               RuleSet           `json:"rules"`
+	Skat                CardSet           `json:"skat"`
+	DealtSkat           CardSet           `json:"dealtSkat"`
+	Pushed              CardSet           `json:"pushed"`
+	Players             [3]PlayerSnapshot `json:"players"`
+	Modifiers           GameModifier      `json:"modifiers"`
+	LossReason          string            `json:"lossReason"`
+	Bidding             *BiddingSnapshot  `json:"bidding"`
+	Playing             *PlayingSnapshot  `json:"playing"`
+	JackStrength        int               `json:"jackStrength"`
+	FinalGameValue      int               `json:"finalGameValue"`
+	KontraPlayer        int               `json:"kontraPlayer"`
+	KontraDeclarerCards int               `json:"kontraDeclarerCards"`
+	Multiplier          int               `json:"multiplier"`
+	ForceJunk           bool              `json:"forceJunk"`
+	Claim               *Claim            `json:"claim"`
+	SkatPasses          []SkatPass        `json:"skatPasses"`
+	SkatTaken           bool              `json:"skatTaken"`
+	SealedDeal          *SealedDeal       `json:"sealedDeal,omitempty"`
+}
+
+func isPlayerOrNone(player int) bool {
+	return player >= PlayerNone && player < 3
+}
+
+// Test whether the cards are distinct and count them
+func countDistinctCards(sets ...CardSet) (int, bool) {
+	seen := make(map[Card]bool)
+	for _, set := range sets {
+		for _, card := range set {
+			if seen[card] {
+				return 0, false
+			}
+			seen[card] = true
+		}
+	}
+	return len(seen), true
+}
+
+func (b *BiddingState) Snapshot() *BiddingSnapshot {
+	result := &BiddingSnapshot{
+		Declarer:         b.declarer,
+		AwaitingResponse: b.awaitingResponse,
+		LastBid:          b.lastBid,
+	}
+	for i := range b.players {
+		result.Players[i] = BiddingPlayerSnapshot{
+			LastBid:      b.players[i].LastBid,
+			HasPassedBid: b.players[i].HasPassedBid,
+		}
+	}
+	return result
+}
+
+// Recreate a bidding state from a snapshot
+//
+// The bids are checked against the bid ladder of the rules.
+func RestoreBiddingState(snap *BiddingSnapshot, rules *RuleSet) (*BiddingState, error) {
+	result := NewBiddingStateForRules(rules)
+	if !isPlayerOrNone(snap.Declarer) {
+		return nil, ErrInconsistentSnapshot
+	}
+	if snap.LastBid != BidNone && !isOnLadder(result.ladder, snap.LastBid) {
+		return nil, ErrInconsistentSnapshot
+	}
+	if snap.AwaitingResponse && snap.LastBid == BidNone {
+		return nil, ErrInconsistentSnapshot
+	}
+	for i, player := range snap.Players {
+		if player.LastBid != BidNone && (!isOnLadder(result.ladder, player.LastBid) || player.LastBid > snap.LastBid) {
+			return nil, ErrInconsistentSnapshot
+		}
+		result.players[i] = BiddingPlayerState{
+			LastBid:      player.LastBid,
+			HasPassedBid: player.HasPassedBid,
+		}
+	}
+	result.declarer = snap.Declarer
+	result.awaitingResponse = snap.AwaitingResponse
+	result.lastBid = snap.LastBid
+	return result, nil
+}
+
+func (s *PlayingState) Snapshot() *PlayingSnapshot {
+	result := &PlayingSnapshot{
+		Forehand:        s.forehand,
+		Current:         s.current,
+		Declarer:        s.declarer,
+		GameType:        s.gameType,
+		LastTrick:       s.lastTrick,
+		LastTrickWinner: s.lastTrickWinner,
+		Table:           s.table.Copy(),
+		History:         s.GetTrickHistory(),
+		Skat:            s.skat.Copy(),
+	}
+	for i := range s.players {
+		result.Players[i] = PlayingPlayerSnapshot{
+			Hand:     s.players[i].Hand.Copy(),
+			WonCards: s.players[i].WonCards.Copy(),
+			Tricks:   s.players[i].Tricks,
+		}
+	}
+	return result
+}
+
+// Recreate a playing state from a snapshot
+//
+// All 32 cards must be accounted for and the hands must match the number of
+// tricks played so far.
+func RestorePlayingState(snap *PlayingSnapshot) (*PlayingState, error) {
+	return restorePlayingState(snap, false)
+}
+
+// With a sealed deal, the hands are unknown, so only the cards played so far
+// are checked
+func restorePlayingState(snap *PlayingSnapshot, sealed bool) (*PlayingState, error) {
+	if snap.Forehand < 0 || snap.Forehand >= 3 || snap.Current < 0 || snap.Current >= 3 {
+		return nil, ErrInconsistentSnapshot
+	}
+	if !isPlayerOrNone(snap.Declarer) || !isPlayerOrNone(snap.LastTrickWinner) {
+		return nil, ErrInconsistentSnapshot
+	}
+	if snap.GameType == InvalidGameType || snap.GameType.Pretty() == "" {
+		return nil, ErrInconsistentSnapshot
+	}
+	if (snap.GameType == GameTypeJunk) != (snap.Declarer == PlayerNone) {
+		return nil, ErrInconsistentSnapshot
+	}
+	if len(snap.Table) >= 3 || snap.Current != (snap.Forehand+len(snap.Table))%3 {
+		return nil, ErrInconsistentSnapshot
+	}
+
+	// once the remaining cards have been awarded, the hands are empty and
+	// the trick history is incomplete
+	awarded := len(snap.Table) == 0
+	sets := []CardSet{snap.Table, snap.Skat}
+	tricks := 0
+	for _, player := range snap.Players {
+		sets = append(sets, player.Hand, player.WonCards)
+		tricks = tricks + player.Tricks
+		awarded = awarded && len(player.Hand) == 0
+	}
+	if sealed {
+		if _, ok := countDistinctCards(sets...); !ok {
+			return nil, ErrInconsistentSnapshot
+		}
+	} else if !awarded {
+		for i, player := range snap.Players {
+			played := len(snap.History)
+			if (i-snap.Forehand+3)%3 < len(snap.Table) {
+				played = played + 1
+			}
+			if len(player.Hand) != 10-played {
+				return nil, ErrInconsistentSnapshot
+			}
+		}
+		if tricks != len(snap.History) {
+			return nil, ErrInconsistentSnapshot
+		}
+	}
+	if n, ok := countDistinctCards(sets...); !sealed && (!ok || n != 32) {
+		return nil, ErrInconsistentSnapshot
+	}
+
+	history := make([]TrickRecord, len(snap.History), 10)
+	copy(history, snap.History)
+	result := &PlayingState{
+		forehand:        snap.Forehand,
+		current:         snap.Current,
+		declarer:        snap.Declarer,
+		gameType:        snap.GameType,
+		lastTrick:       snap.LastTrick,
+		lastTrickWinner: snap.LastTrickWinner,
+		table:           snap.Table.Copy(),
+		history:         history,
+	}
+	if len(snap.Skat) > 0 {
+		result.skat = snap.Skat.Copy()
+	}
+	for i, player := range snap.Players {
+		result.players[i] = PlayingPlayerState{
+			Hand:     player.Hand.Copy(),
+			WonCards: player.WonCards.Copy(),
+			Tricks:   player.Tricks,
+		}
+	}
+	return result, nil
+}
+
+func (g *GameState) Snapshot() *GameSnapshot {
+	result := &GameSnapshot{
+		Version:             SnapshotVersion,
+		Phase:               g.phase,
+		WithDealer:          g.withDealer,
+		ServerSeed:          g.serverSeed,
+		ServerCommitment:    g.serverCommitment,
+		DealerSeed:          g.dealerSeed,
+		DealerCommitment:    g.dealerCommitment,
+		DealerLookingAtHand: g.dealerLookingAtHand,
+		Scoring:             g.scoring,
+		Rules:               g.rules,
+		Skat:                g.skat.Copy(),
+		DealtSkat:           g.dealtSkat.Copy(),
+		Pushed:              g.pushed.Copy(),
+		Modifiers:           g.modifiers,
+		LossReason:          g.lossReason,
+		JackStrength:        g.jackStrength,
+		FinalGameValue:      g.finalGameValue,
+		KontraPlayer:        g.kontraPlayer,
+		KontraDeclarerCards: g.kontraDeclarerCards,
+		Multiplier:          g.multiplier,
+		ForceJunk:           g.forceJunk,
+		SkatTaken:           g.skatTaken,
+	}
+	for i := range g.players {
+		p := &g.players[i]
+		result.Players[i] = PlayerSnapshot{
+			SeedCommitment:   p.SeedCommitment,
+			Seed:             p.Seed,
+			Hand:             p.Hand.Copy(),
+			DealtHand:        p.DealtHand.Copy(),
+			WonCards:         p.WonCards.Copy(),
+			Score:            p.Score,
+			Resigned:         p.Resigned,
+			Exchanged:        p.Exchanged,
+			PeekingLastTrick: p.PeekingLastTrick,
+			PeekingSkat:      p.PeekingSkat,
+		}
+	}
+	if g.biddingState != nil {
+		result.Bidding = g.biddingState.Snapshot()
+	}
+	if g.playingState != nil {
+		result.Playing = g.playingState.Snapshot()
+	}
+	if g.claim != nil {
+		claim := *g.claim
+		result.Claim = &claim
+	}
+	if g.skatPasses != nil {
+		result.SkatPasses = make([]SkatPass, len(g.skatPasses))
+		copy(result.SkatPasses, g.skatPasses)
+	}
+	if d := g.SealedDeal(); d != nil {
+		result.SealedDeal = d.copy(true)
+	}
+	return result
+}
+
+// Return true while the hands of a snapshot are sealed
+func (snap *GameSnapshot) sealed() bool {
+	return snap.SealedDeal != nil && !snap.SealedDeal.Audited()
+}
+
+func (snap *GameSnapshot) check() error {
+	if snap.Version != SnapshotVersion {
+		return ErrSnapshotVersion
+	}
+	if snap.Phase < PhaseInit || snap.Phase > PhaseAudit {
+		return ErrInconsistentSnapshot
+	}
+	if err := snap.Rules.Validate(); err != nil {
+		return err
+	}
+	if snap.Multiplier < 1 {
+		return ErrInconsistentSnapshot
+	}
+	if !isPlayerOrNone(snap.DealerLookingAtHand) || !isPlayerOrNone(snap.KontraPlayer) {
+		return ErrInconsistentSnapshot
+	}
+	if snap.Claim != nil && (snap.Claim.Player < 0 || snap.Claim.Player >= 3) {
+		return ErrInconsistentSnapshot
+	}
+	if len(snap.SkatPasses) > 3 {
+		return ErrInconsistentSnapshot
+	}
+	if !VerifySeedCommitment(snap.ServerSeed, snap.ServerCommitment) {
+		return ErrInconsistentSnapshot
+	}
+	for _, player := range snap.Players {
+		if player.Seed != nil && !VerifySeedCommitment(player.Seed, player.SeedCommitment) {
+			return ErrInconsistentSnapshot
+		}
+	}
+	if snap.DealerSeed != nil && !VerifySeedCommitment(snap.DealerSeed, snap.DealerCommitment) {
+		return ErrInconsistentSnapshot
+	}
+	if (snap.SealedDeal != nil) != (snap.Rules.Dealing == DealingMentalPoker) {
+		return ErrInconsistentSnapshot
+	}
+	if snap.SealedDeal != nil {
+		if err := snap.SealedDeal.check(); err != nil {
+			return err
+		}
+	}
+
+	if snap.Phase == PhaseInit {
+		if snap.Bidding != nil || snap.Playing != nil {
+			return ErrInconsistentSnapshot
+		}
+		return nil
+	}
+
+	if snap.Bidding == nil {
+		return ErrInconsistentSnapshot
+	}
+	if !snap.sealed() {
+		if len(snap.DealtSkat) != 2 {
+			return ErrInconsistentSnapshot
+		}
+		sets := []CardSet{snap.DealtSkat}
+		for _, player := range snap.Players {
+			if len(player.DealtHand) != 10 {
+				return ErrInconsistentSnapshot
+			}
+			sets = append(sets, player.DealtHand)
+		}
+		if n, ok := countDistinctCards(sets...); !ok || n != 32 {
+			return ErrInconsistentSnapshot
+		}
+	}
+
+	switch snap.Phase {
+	case PhaseDeclaration, PhaseExchange:
+		if snap.Bidding.Declarer == PlayerNone {
+			return ErrInconsistentSnapshot
+		}
+	case PhasePlaying:
+		if snap.Playing == nil {
+			return ErrInconsistentSnapshot
+		}
+	}
+	if snap.Playing != nil && snap.Playing.Declarer != snap.Bidding.Declarer {
+		return ErrInconsistentSnapshot
+	}
+	return nil
+}
+
+// Recreate a game from a snapshot
+//
+// Returns ErrSnapshotVersion if the snapshot was written by an incompatible
+// version and ErrInconsistentSnapshot if the state does not make sense.
+func RestoreGame(snap *GameSnapshot) (*GameState, error) {
+	if err := snap.check(); err != nil {
+		return nil, err
+	}
+
+	result := &GameState{
+		phase:               snap.Phase,
+		withDealer:          snap.WithDealer,
+		serverSeed:          snap.ServerSeed,
+		serverCommitment:    snap.ServerCommitment,
+		dealerSeed:          snap.DealerSeed,
+		dealerCommitment:    snap.DealerCommitment,
+		dealerLookingAtHand: snap.DealerLookingAtHand,
+		scoring:             snap.Scoring,
+		rules:               snap.Rules,
+		dealing:             NewDealingStrategy(snap.Rules.Dealing),
+		skat:                snap.Skat.Copy(),
+		dealtSkat:           snap.DealtSkat.Copy(),
+		pushed:              snap.Pushed.Copy(),
+		modifiers:           snap.Modifiers,
+		lossReason:          snap.LossReason,
+		jackStrength:        snap.JackStrength,
+		finalGameValue:      snap.FinalGameValue,
+		kontraPlayer:        snap.KontraPlayer,
+		kontraDeclarerCards: snap.KontraDeclarerCards,
+		multiplier:          snap.Multiplier,
+		forceJunk:           snap.ForceJunk,
+		skatTaken:           snap.SkatTaken,
+	}
+	for i, p := range snap.Players {
+		result.players[i] = CommonPlayerState{
+			SeedCommitment:   p.SeedCommitment,
+			Seed:             p.Seed,
+			Hand:             p.Hand.Copy(),
+			DealtHand:        p.DealtHand.Copy(),
+			WonCards:         p.WonCards.Copy(),
+			Score:            p.Score,
+			Resigned:         p.Resigned,
+			Exchanged:        p.Exchanged,
+			PeekingLastTrick: p.PeekingLastTrick,
+			PeekingSkat:      p.PeekingSkat,
+		}
+	}
+	if snap.Bidding != nil {
+		bidding, err := RestoreBiddingState(snap.Bidding, &result.rules)
+		if err != nil {
+			return nil, err
+		}
+		result.biddingState = bidding
+	}
+	if snap.Playing != nil {
+		playing, err := restorePlayingState(snap.Playing, snap.sealed())
+		if err != nil {
+			return nil, err
+		}
+		result.playingState = playing
+	}
+	if snap.Claim != nil {
+		claim := *snap.Claim
+		result.claim = &claim
+	}
+	if snap.SkatPasses != nil {
+		result.skatPasses = make([]SkatPass, len(snap.SkatPasses))
+		copy(result.skatPasses, snap.SkatPasses)
+	}
+	if snap.SealedDeal != nil {
+		result.dealing.(*MentalPokerDealing).deal = *snap.SealedDeal.copy(true)
+	}
+	return result, nil
+}
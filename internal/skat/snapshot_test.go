@@ -0,0 +1,148 @@
+package skat
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testEncodeSnapshot(t *testing.T, g *GameState) []byte {
+	data, err := json.Marshal(g.Snapshot())
+	assert.Nil(t, err)
+	return data
+}
+
+func testRestoreSnapshot(t *testing.T, data []byte) (*GameState, error) {
+	snap := &GameSnapshot{}
+	assert.Nil(t, json.Unmarshal(data, snap))
+	return RestoreGame(snap)
+}
+
+func testSnapshotRoundTrip(t *testing.T, g *GameState) *GameState {
+	data := testEncodeSnapshot(t, g)
+	restored, err := testRestoreSnapshot(t, data)
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), string(testEncodeSnapshot(t, restored)))
+	for i := 0; i < 3; i = i + 1 {
+		assert.Equal(t, g.BlindedForPlayer(i), restored.BlindedForPlayer(i))
+	}
+	return restored
+}
+
+func TestGameSnapshot(t *testing.T) {
+	t.Run("init phase keeps missing seeds apart from empty ones", func(t *testing.T) {
+		g := testGetInitPhaseGame(t)
+		testCommitSeeds(t, g)
+		assert.Nil(t, g.SetSeed(PlayerInitialMiddlehand, []byte{}))
+		restored := testSnapshotRoundTrip(t, g)
+		blinded := restored.BlindedForPlayer(PlayerInitialForehand)
+		assert.False(t, blinded.Players[PlayerInitialForehand].SeedProvided)
+		assert.True(t, blinded.Players[PlayerInitialMiddlehand].SeedProvided)
+	})
+
+	t.Run("bidding phase", func(t *testing.T) {
+		g := testGetBiddingPhaseGame(t)
+		assert.Nil(t, g.CallBid(PlayerInitialMiddlehand, 18))
+		restored := testSnapshotRoundTrip(t, g)
+		assert.Equal(t, ErrNotYourTurn, restored.CallBid(PlayerInitialMiddlehand, 20))
+		assert.Nil(t, restored.RespondToBid(PlayerInitialForehand, true))
+		assert.Equal(t, ErrBidTooLow, restored.CallBid(PlayerInitialMiddlehand, 18))
+	})
+
+	t.Run("declaration phase", func(t *testing.T) {
+		g := testGetDeclarationPhaseGame(t)
+		restored := testSnapshotRoundTrip(t, g)
+		assert.Nil(t, restored.Declare(PlayerInitialMiddlehand, GameTypeGrand, NoGameModifiers, nil))
+	})
+
+	t.Run("playing phase continues like the original", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeSpades)
+		assert.Nil(t, g.Kontra(PlayerInitialForehand))
+		testPlayOut(t, g.Playing(), g.PlayCard, 3)
+		play := g.Playing()
+		player := play.GetCurrentPlayer()
+		assert.Nil(t, g.PlayCard(player, play.LegalCards(player)[0]))
+
+		restored := testSnapshotRoundTrip(t, g)
+		testPlayOut(t, g.Playing(), g.PlayCard, -1)
+		testPlayOut(t, restored.Playing(), restored.PlayCard, -1)
+		assert.Equal(t, PhaseScored, restored.Phase())
+		for i := 0; i < 3; i = i + 1 {
+			assert.Equal(t, g.GetScore(i), restored.GetScore(i))
+		}
+		assert.Equal(t, g.Reveal(), restored.Reveal())
+	})
+
+	t.Run("scored games", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeNull)
+		testPlayOut(t, g.Playing(), g.PlayCard, -1)
+		testSnapshotRoundTrip(t, g)
+
+		g = testGetDeclarationPhaseGame(t)
+		assert.Nil(t, g.Resign(PlayerInitialMiddlehand))
+		testSnapshotRoundTrip(t, g)
+	})
+
+	t.Run("junk pushing and exchange phases", func(t *testing.T) {
+		g := testGetJunkPushingPhaseGame(t)
+		assert.Nil(t, g.TakeSkat(PlayerInitialForehand))
+		restored := testSnapshotRoundTrip(t, g)
+		assert.Equal(t, ErrInvalidPush, restored.PassSkatOn(PlayerInitialForehand))
+
+		g = testGetExchangePhaseGame(t)
+		assert.Nil(t, g.ExchangeCards(PlayerInitialForehand, nil, nil))
+		restored = testSnapshotRoundTrip(t, g)
+		assert.Nil(t, restored.ExchangeCards(PlayerInitialRearhand, nil, nil))
+		assert.Equal(t, PhasePlaying, restored.Phase())
+	})
+
+	t.Run("rejects other versions", func(t *testing.T) {
+		snap := testGetBiddingPhaseGame(t).Snapshot()
+		snap.Version = SnapshotVersion + 1
+		_, err := RestoreGame(snap)
+		assert.Equal(t, ErrSnapshotVersion, err)
+	})
+
+	t.Run("rejects duplicate cards", func(t *testing.T) {
+		snap := testGetBiddingPhaseGame(t).Snapshot()
+		snap.Players[1].DealtHand[0] = snap.Players[0].DealtHand[0]
+		_, err := RestoreGame(snap)
+		assert.Equal(t, ErrInconsistentSnapshot, err)
+	})
+
+	t.Run("rejects missing cards in play", func(t *testing.T) {
+		snap := testGetPlayingPhaseGame(t, GameTypeGrand).Snapshot()
+		snap.Playing.Players[0].Hand = snap.Playing.Players[0].Hand[1:]
+		_, err := RestoreGame(snap)
+		assert.Equal(t, ErrInconsistentSnapshot, err)
+	})
+
+	t.Run("rejects invalid bids", func(t *testing.T) {
+		g := testGetBiddingPhaseGame(t)
+		assert.Nil(t, g.CallBid(PlayerInitialMiddlehand, 18))
+		snap := g.Snapshot()
+		snap.Bidding.LastBid = 19
+		_, err := RestoreGame(snap)
+		assert.Equal(t, ErrInconsistentSnapshot, err)
+	})
+
+	t.Run("rejects a phase without its state", func(t *testing.T) {
+		snap := testGetPlayingPhaseGame(t, GameTypeGrand).Snapshot()
+		snap.Playing = nil
+		_, err := RestoreGame(snap)
+		assert.Equal(t, ErrInconsistentSnapshot, err)
+	})
+
+	t.Run("rejects seeds which do not match their commitment", func(t *testing.T) {
+		snap := testGetBiddingPhaseGame(t).Snapshot()
+		snap.Players[1].Seed = []byte{1}
+		_, err := RestoreGame(snap)
+		assert.Equal(t, ErrInconsistentSnapshot, err)
+
+		snap = testGetBiddingPhaseGame(t).Snapshot()
+		snap.ServerSeed = []byte{1}
+		_, err = RestoreGame(snap)
+		assert.Equal(t, ErrInconsistentSnapshot, err)
+	})
+}
@@ -0,0 +1,145 @@
+package skat
+
+import (
+	"errors"
+)
+
+var (
+	ErrDealMismatch = errors.New("dealt cards do not match the seeds")
+)
+
+// Concatenate the seeds of all parties into the seed used for shuffling
+func composeSeed(serverSeed []byte, playerSeeds [3][]byte, withDealer bool, dealerSeed []byte) ([]byte, error) {
+	if serverSeed == nil {
+		return nil, ErrMissingSeed
+	}
+	result := make([]byte, len(serverSeed))
+	copy(result, serverSeed)
+	for _, seed := range playerSeeds {
+		if seed == nil {
+			return nil, ErrMissingSeed
+		}
+		result = append(result, seed...)
+	}
+	if withDealer && dealerSeed == nil {
+		return nil, ErrMissingSeed
+	}
+	result = append(result, dealerSeed...)
+	return result, nil
+}
+
+// Test whether two sets hold the same cards, in any order
+func sameCards(a CardSet, b CardSet) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, card := range a {
+		if !b.Contains(card) {
+			return false
+		}
+	}
+	return true
+}
+
+// Check the deal of a scored or passed-in game against the revealed seeds
+//
+// The shuffle is recomputed from the seeds in the state and compared with the
+// hand the player was dealt and with the revealed hands and skat. The hand is
+// ignored for the dealer (PlayerNone). Returns ErrSeedMismatch if the server
+// seed does not match the commitment of the server and ErrDealMismatch if the
+// cards differ.
+func VerifyDeal(gs *BlindedGameState, player int, hand CardSet) error {
+	if gs.Phase != PhaseScored && gs.Phase != PhasePassedIn {
+		return ErrWrongPhase
+	}
+	if !VerifySeedCommitment(gs.ServerSeed, gs.ServerCommitment) {
+		return ErrSeedMismatch
+	}
+
+	var playerSeeds [3][]byte
+	for i := range playerSeeds {
+		if i < len(gs.Players) {
+			playerSeeds[i] = gs.Players[i].Seed
+		}
+	}
+	seed, err := composeSeed(gs.ServerSeed, playerSeeds, gs.WithDealer, gs.DealerSeed)
+	if err != nil {
+		return err
+	}
+	hands, skat, err := DealWithSeed(seed)
+	if err != nil {
+		return err
+	}
+
+	if player != PlayerNone && !sameCards(hands[player], hand) {
+		return ErrDealMismatch
+	}
+	if gs.Reveal != nil {
+		if len(gs.Reveal.Hands) != len(hands) {
+			return ErrDealMismatch
+		}
+		for i := range hands {
+			if !sameCards(hands[i], gs.Reveal.Hands[i]) {
+				return ErrDealMismatch
+			}
+		}
+		if !sameCards(skat, gs.Reveal.Skat) {
+			return ErrDealMismatch
+		}
+	}
+	return nil
+}
+
+// Check a scored game with a sealed deal against the revealed keys
+//
+// Every slot is decrypted with the keys in the state and compared with the
+// hand the player decrypted for themselves, with the cards played and with the
+// revealed hands and skat. The hand is ignored for the dealer (PlayerNone).
+// Returns ErrInvalidKey if a key does not match the key the player published
+// and ErrDealMismatch if the cards differ.
+func VerifySealedDeal(gs *BlindedGameState, player int, hand CardSet) error {
+	if gs.Phase != PhaseScored {
+		return ErrWrongPhase
+	}
+	d := gs.SealedDeal
+	if d == nil || !d.Audited() {
+		return ErrMissingKey
+	}
+	for i, data := range d.SecretKeys {
+		key, err := ParseMentalPokerKey(data)
+		if err != nil {
+			return err
+		}
+		if !key.Matches(d.PublicKeys[i]) {
+			return ErrInvalidKey
+		}
+	}
+	cards, err := d.openSlots()
+	if err != nil {
+		return err
+	}
+	hands, skat := sealedLayout()
+
+	if player != PlayerNone && !sameCards(slotCards(cards, hands[player]), hand) {
+		return ErrDealMismatch
+	}
+	for _, play := range d.Plays {
+		if cards[play.Slot] != play.Card {
+			return ErrDealMismatch
+		}
+	}
+	if gs.Reveal != nil {
+		if len(gs.Reveal.Hands) != len(hands) {
+			return ErrDealMismatch
+		}
+		for i := range hands {
+			if !sameCards(slotCards(cards, hands[i]), gs.Reveal.Hands[i]) {
+				return ErrDealMismatch
+			}
+		}
+		if !sameCards(slotCards(cards, skat), gs.Reveal.Skat) {
+			return ErrDealMismatch
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package skat
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testGetScoredBlinded(t *testing.T, player int) (*BlindedGameState, CardSet) {
+	g := testGetScoredGame(t, GameTypeGrand, false)
+	hands, _ := g.Dealt()
+	return g.BlindedForPlayer(player), hands[player]
+}
+
+func TestVerifyDeal(t *testing.T) {
+	t.Run("accepts a fair deal", func(t *testing.T) {
+		gs, hand := testGetScoredBlinded(t, PlayerInitialMiddlehand)
+		assert.Nil(t, VerifyDeal(gs, PlayerInitialMiddlehand, hand))
+	})
+
+	t.Run("ignores the order of the hand", func(t *testing.T) {
+		gs, hand := testGetScoredBlinded(t, PlayerInitialForehand)
+		hand[0], hand[9] = hand[9], hand[0]
+		assert.Nil(t, VerifyDeal(gs, PlayerInitialForehand, hand))
+	})
+
+	t.Run("accepts a fair deal after transmission", func(t *testing.T) {
+		gs, hand := testGetScoredBlinded(t, PlayerInitialRearhand)
+		data, err := json.Marshal(gs)
+		assert.Nil(t, err)
+		received := &BlindedGameState{}
+		assert.Nil(t, json.Unmarshal(data, received))
+		assert.Nil(t, VerifyDeal(received, PlayerInitialRearhand, hand))
+	})
+
+	t.Run("checks only the reveal for the dealer", func(t *testing.T) {
+		gs, _ := testGetScoredBlinded(t, PlayerInitialForehand)
+		assert.Nil(t, VerifyDeal(gs, PlayerNone, nil))
+	})
+
+	t.Run("rejects a different hand", func(t *testing.T) {
+		gs, _ := testGetScoredBlinded(t, PlayerInitialForehand)
+		other := gs.Reveal.Hands[PlayerInitialMiddlehand]
+		assert.Equal(t, ErrDealMismatch, VerifyDeal(gs, PlayerInitialForehand, other))
+	})
+
+	t.Run("rejects a different reveal", func(t *testing.T) {
+		gs, hand := testGetScoredBlinded(t, PlayerInitialForehand)
+		gs.Reveal.Skat[0], gs.Reveal.Hands[PlayerInitialRearhand][0] = gs.Reveal.Hands[PlayerInitialRearhand][0], gs.Reveal.Skat[0]
+		assert.Equal(t, ErrDealMismatch, VerifyDeal(gs, PlayerInitialForehand, hand))
+	})
+
+	t.Run("rejects different seeds", func(t *testing.T) {
+		gs, hand := testGetScoredBlinded(t, PlayerInitialForehand)
+		gs.Players[PlayerInitialRearhand].Seed = []byte{1}
+		assert.Equal(t, ErrDealMismatch, VerifyDeal(gs, PlayerInitialForehand, hand))
+	})
+
+	t.Run("rejects a server seed which does not match the commitment", func(t *testing.T) {
+		gs, hand := testGetScoredBlinded(t, PlayerInitialForehand)
+		gs.ServerSeed = []byte{1}
+		assert.Equal(t, ErrSeedMismatch, VerifyDeal(gs, PlayerInitialForehand, hand))
+	})
+
+	t.Run("accepts a fair deal which was passed in", func(t *testing.T) {
+		rules := StandardRuleSet()
+		rules.AllPass = AllPassRedeal
+		g := testNewDealtGame(t, rules)
+		testPassBidding(t, g)
+		hands, _ := g.Dealt()
+		gs := g.BlindedForPlayer(PlayerInitialForehand)
+		assert.Equal(t, PhasePassedIn, gs.Phase)
+		assert.NotNil(t, gs.Reveal)
+		assert.Nil(t, VerifyDeal(gs, PlayerInitialForehand, hands[PlayerInitialForehand]))
+		assert.Equal(t, ErrDealMismatch, VerifyDeal(gs, PlayerInitialForehand, hands[PlayerInitialMiddlehand]))
+	})
+
+	t.Run("rejects games which have not been scored", func(t *testing.T) {
+		g := testGetPlayingPhaseGame(t, GameTypeGrand)
+		assert.Equal(t, ErrWrongPhase, VerifyDeal(g.BlindedForPlayer(PlayerInitialForehand), PlayerInitialForehand, nil))
+	})
+}